@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+	"github.com/RafOSS-br/K8sVersioner/kubernetes"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	initDryRun          bool
+	initKubeconfig      string
+	initTargetNamespace string
+	initMode            string
+	initFromExisting    bool
+)
+
+var initCmd = &cobra.Command{
+	Use:     "init",
+	Short:   "Scaffold a starter Config/GitConfig to onboard a new cluster",
+	GroupID: managementGroupID,
+	Long: `init turns first-time onboarding into a single command instead of
+hand-authoring YAML: in "file" mode it writes a starter config.json; in
+"kube-controller" mode (the default) it applies the Config/GitConfig CRDs
+plus a sample Config and GitConfig CR into --target-namespace. With
+--from-existing, the sample Config's includeResource list is seeded from
+the common workload kinds actually present in --target-namespace instead
+of a generic placeholder list.`,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false, "Print what would be created instead of applying/writing it")
+	initCmd.Flags().StringVar(&initKubeconfig, "kubeconfig", "", "Path to a kubeconfig file; defaults to in-cluster config, then the default kubeconfig location")
+	initCmd.Flags().StringVar(&initTargetNamespace, "target-namespace", "default", "Namespace the generated GitConfig/Config CRs (or config.json Config entry) target")
+	initCmd.Flags().StringVar(&initMode, "mode", "kube-controller", `Onboarding mode: "kube-controller" (apply CRDs+CR) or "file" (write config.json)`)
+	initCmd.Flags().BoolVar(&initFromExisting, "from-existing", false, "Seed the sample Config's includeResource list from kinds already present in --target-namespace")
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	switch initMode {
+	case "file":
+		return initFileMode()
+	case "kube-controller":
+		return initKubeControllerMode(cmd.Context())
+	default:
+		return fmt.Errorf(`unknown --mode %q, want "file" or "kube-controller"`, initMode)
+	}
+}
+
+// fileSeed is the shape written to config.json by --mode=file: a GitConfig
+// and Config spec pair a user fills in and converts into CRs, rather than
+// a format K8sVersioner itself loads at runtime.
+type fileSeed struct {
+	GitConfig config.GitConfigSpec `json:"gitConfig"`
+	Config    config.ConfigSpec    `json:"config"`
+}
+
+func initFileMode() error {
+	resources := defaultIncludeResources()
+
+	seed := fileSeed{
+		GitConfig: sampleGitConfigSpec(initTargetNamespace),
+		Config:    sampleConfigSpec(initTargetNamespace, "sample-git", resources),
+	}
+
+	data, err := json.MarshalIndent(seed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to render starter config.json: %w", err)
+	}
+
+	if initDryRun {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile("config.json", data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config.json: %w", err)
+	}
+	log.Info().Str("path", "config.json").Msg("Wrote starter config.json; fill in GitConfig credentials before converting it into GitConfig/Config CRs")
+	return nil
+}
+
+func initKubeControllerMode(ctx context.Context) error {
+	resources := defaultIncludeResources()
+
+	var dynClient dynamic.Interface
+	if initFromExisting || !initDryRun {
+		k8sClient, err := kubernetes.GetKubernetesConfigFromPath(initKubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to build Kubernetes client: %w", err)
+		}
+		dynClient = k8sClient.GetDynamicClient()
+	}
+
+	if initFromExisting {
+		discovered, err := discoverIncludeResources(ctx, dynClient, initTargetNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot existing resources: %w", err)
+		}
+		resources = discovered
+	}
+
+	gitConfig := sampleGitConfig(initTargetNamespace)
+	versioningConfig := sampleConfig(initTargetNamespace, gitConfig.Name, resources)
+
+	objects := []*unstructured.Unstructured{
+		crdFor(config.GitConfigGVR, "GitConfig", "GitConfigList"),
+		crdFor(config.ConfigGVR, "Config", "ConfigList"),
+		toUnstructured(gitConfig),
+		toUnstructured(versioningConfig),
+	}
+
+	if initDryRun {
+		for _, obj := range objects {
+			printYAML(obj)
+		}
+		return nil
+	}
+
+	crdGVR := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	for _, crd := range objects[:2] {
+		if _, err := dynClient.Resource(crdGVR).Apply(ctx, crd.GetName(), crd, metav1.ApplyOptions{FieldManager: "k8sversioner-init"}); err != nil {
+			return fmt.Errorf("failed to apply CRD %s: %w", crd.GetName(), err)
+		}
+	}
+
+	if _, err := dynClient.Resource(config.GitConfigGVR).Namespace(initTargetNamespace).Apply(ctx, gitConfig.Name, objects[2], metav1.ApplyOptions{FieldManager: "k8sversioner-init"}); err != nil {
+		return fmt.Errorf("failed to apply sample GitConfig: %w", err)
+	}
+	if _, err := dynClient.Resource(config.ConfigGVR).Namespace(initTargetNamespace).Apply(ctx, versioningConfig.Name, objects[3], metav1.ApplyOptions{FieldManager: "k8sversioner-init"}); err != nil {
+		return fmt.Errorf("failed to apply sample Config: %w", err)
+	}
+
+	log.Info().Str("namespace", initTargetNamespace).Msg("Applied the Config/GitConfig CRDs and a sample Config; edit the GitConfig's credentialsSecretRef before use")
+	return nil
+}
+
+func sampleGitConfigSpec(namespace string) config.GitConfigSpec {
+	return config.GitConfigSpec{
+		Protocol:             "https",
+		RepositoryURL:        "github.com/example/k8sversioner-history.git",
+		Branch:               "main",
+		CredentialsSecretRef: corev1.SecretReference{Name: "sample-git-credentials", Namespace: namespace},
+		RepositoryPath:       config.DefaultRepositoryPath,
+		RepositoryFolder:     config.DefaultRepositoryFolder,
+	}
+}
+
+func sampleGitConfig(namespace string) *config.GitConfig {
+	return &config.GitConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: config.GroupVersion.String(), Kind: "GitConfig"},
+		ObjectMeta: metav1.ObjectMeta{Name: "sample-git", Namespace: namespace},
+		Spec:       sampleGitConfigSpec(namespace),
+	}
+}
+
+func sampleConfigSpec(namespace, gitRef string, resources []config.ResourceFilter) config.ConfigSpec {
+	return config.ConfigSpec{
+		Namespace:       namespace,
+		IncludeResource: resources,
+		OutputType:      "yaml",
+		GitRef:          gitRef,
+		FolderStructure: "{{.Cluster}}/{{.Namespace}}/{{.Kind}}/{{.Name}}.yaml",
+	}
+}
+
+func sampleConfig(namespace, gitRef string, resources []config.ResourceFilter) *config.Config {
+	return &config.Config{
+		TypeMeta:   metav1.TypeMeta{APIVersion: config.GroupVersion.String(), Kind: "Config"},
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: namespace},
+		Spec:       sampleConfigSpec(namespace, gitRef, resources),
+	}
+}
+
+func defaultIncludeResources() []config.ResourceFilter {
+	return []config.ResourceFilter{
+		{Name: "Deployment", APIVersion: "apps/v1"},
+		{Name: "ConfigMap", APIVersion: "v1"},
+		{Name: "Service", APIVersion: "v1"},
+	}
+}
+
+// candidateIncludeResources is the set of common workload kinds
+// --from-existing checks for before falling back to defaultIncludeResources.
+var candidateIncludeResources = []struct {
+	filter config.ResourceFilter
+	gvr    schema.GroupVersionResource
+}{
+	{config.ResourceFilter{Name: "Deployment", APIVersion: "apps/v1"}, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{config.ResourceFilter{Name: "StatefulSet", APIVersion: "apps/v1"}, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}},
+	{config.ResourceFilter{Name: "ConfigMap", APIVersion: "v1"}, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}},
+	{config.ResourceFilter{Name: "Service", APIVersion: "v1"}, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}},
+	{config.ResourceFilter{Name: "Ingress", APIVersion: "networking.k8s.io/v1"}, schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}},
+}
+
+// discoverIncludeResources snapshots which of candidateIncludeResources
+// actually have instances in namespace, so --from-existing seeds the
+// sample Config with what's really there instead of a generic list.
+func discoverIncludeResources(ctx context.Context, dynClient dynamic.Interface, namespace string) ([]config.ResourceFilter, error) {
+	var found []config.ResourceFilter
+	for _, candidate := range candidateIncludeResources {
+		list, err := dynClient.Resource(candidate.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", candidate.gvr.Resource, err)
+		}
+		if len(list.Items) > 0 {
+			found = append(found, candidate.filter)
+		}
+	}
+	if len(found) == 0 {
+		return defaultIncludeResources(), nil
+	}
+	return found, nil
+}
+
+// crdFor builds a minimal apiextensions.k8s.io/v1 CustomResourceDefinition
+// for gvr/kind, with a permissive schema: K8sVersioner validates Config and
+// GitConfig specs itself after unmarshaling (see config.LoadConfigs), so the
+// CRD only needs to let the API server accept and store the object.
+func crdFor(gvr schema.GroupVersionResource, kind, listKind string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName(gvr.Resource + "." + gvr.Group)
+
+	_ = unstructured.SetNestedMap(crd.Object, map[string]interface{}{
+		"group": gvr.Group,
+		"names": map[string]interface{}{
+			"kind":     kind,
+			"listKind": listKind,
+			"plural":   gvr.Resource,
+			"singular": strings.ToLower(kind),
+		},
+		"scope": "Namespaced",
+		"versions": []interface{}{
+			map[string]interface{}{
+				"name":    gvr.Version,
+				"served":  true,
+				"storage": true,
+				"subresources": map[string]interface{}{
+					"status": map[string]interface{}{},
+				},
+				"schema": map[string]interface{}{
+					"openAPIV3Schema": map[string]interface{}{
+						"type":                                 "object",
+						"x-kubernetes-preserve-unknown-fields": true,
+					},
+				},
+			},
+		},
+	}, "spec")
+
+	return crd
+}
+
+// toUnstructured converts a Config/GitConfig Go value to the unstructured
+// form the dynamic client's Apply expects, the inverse of the
+// FromUnstructured conversion LoadConfigs/LoadGitConfigs already use.
+func toUnstructured(obj interface{}) *unstructured.Unstructured {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		// obj is always one of the package-local Config/GitConfig types
+		// built just above, so a failure here is a programming error.
+		panic(fmt.Errorf("failed to convert %T to unstructured: %w", obj, err))
+	}
+	return &unstructured.Unstructured{Object: data}
+}
+
+func printYAML(obj *unstructured.Unstructured) {
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		log.Error().Err(err).Msg("Error rendering dry-run object")
+		return
+	}
+	fmt.Printf("---\n%s", data)
+}
@@ -5,28 +5,57 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/RafOSS-br/K8sVersioner/config"
 	"github.com/RafOSS-br/K8sVersioner/controllers"
+	"github.com/RafOSS-br/K8sVersioner/health"
 	"github.com/RafOSS-br/K8sVersioner/kubernetes"
+	"github.com/RafOSS-br/K8sVersioner/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
+var (
+	leaderElection bool
+	leaseNamespace string
+	leaseName      string
+	leaseDuration  time.Duration
+	renewDeadline  time.Duration
+	retryPeriod    time.Duration
+)
+
 var kubeControllerSubCmd = &cobra.Command{
-	Use:   "kube-controller",
-	Short: "KubeController is a tool to manage Kubernetes resources versions",
+	Use:     "kube-controller",
+	Short:   "KubeController is a tool to manage Kubernetes resources versions",
+	GroupID: managementGroupID,
 	Run: func(cmd *cobra.Command, args []string) {
 		run(
 			&config.EnvironmentConfig{
 				OneShot:       oneShot,
 				ExecutionMode: "kube-controller",
+
+				LeaderElection: leaderElection,
+				LeaseNamespace: leaseNamespace,
+				LeaseName:      leaseName,
+				LeaseDuration:  leaseDuration,
+				RenewDeadline:  renewDeadline,
+				RetryPeriod:    retryPeriod,
 			},
 			kubeController,
 		)
 	},
 }
 
+func init() {
+	kubeControllerSubCmd.Flags().BoolVar(&leaderElection, "leader-election", false, "Enable Lease-based leader election so only one replica reconciles at a time")
+	kubeControllerSubCmd.Flags().StringVar(&leaseNamespace, "lease-namespace", "default", "Namespace holding the leader election Lease")
+	kubeControllerSubCmd.Flags().StringVar(&leaseName, "lease-name", "k8sversioner-leader", "Name of the leader election Lease")
+	kubeControllerSubCmd.Flags().DurationVar(&leaseDuration, "lease-duration", 15*time.Second, "Leader election lease duration")
+	kubeControllerSubCmd.Flags().DurationVar(&renewDeadline, "renew-deadline", 10*time.Second, "Leader election renew deadline")
+	kubeControllerSubCmd.Flags().DurationVar(&retryPeriod, "retry-period", 2*time.Second, "Leader election retry period")
+}
+
 func kubeController(envConf *config.EnvironmentConfig) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -34,6 +63,12 @@ func kubeController(envConf *config.EnvironmentConfig) {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
+	go func() {
+		if err := metrics.StartServer(ctx, metricsAddr, metrics.State); err != nil {
+			log.Error().Err(err).Msg("Error starting metrics server")
+		}
+	}()
+
 	k8sClient, err := kubernetes.GetKubernetesConfig()
 	if err != nil {
 		panic(err)
@@ -41,30 +76,52 @@ func kubeController(envConf *config.EnvironmentConfig) {
 
 	cfg, err := config.LoadConfigStore(k8sClient.GetDynamicClient())
 	if err != nil {
+		metrics.State.SetConfigLoaded(false)
 		if config.HandleValidationErrors(ctx, err) {
 			os.Exit(1)
 		}
 		log.Fatal().Err(err).Msg("Error loading configuration")
 		os.Exit(1)
 	}
+	metrics.State.SetConfigLoaded(true)
+
+	cfgManager := config.NewConfigManager(cfg)
 
 	go func() {
-		if err := controllers.StartController(ctx, controllers.ControllerArgs{
-			CfgManager:        config.NewConfigManager(cfg),
-			K8sClient:         k8sClient,
-			EnvironmentConfig: envConf,
-		}); err != nil {
-			log.Error().Err(err).Msg("Error starting controller")
+		if envConf.OneShot {
+			if err := controllers.StartController(ctx, controllers.ControllerArgs{
+				CfgManager:        cfgManager,
+				K8sClient:         k8sClient,
+				EnvironmentConfig: envConf,
+			}); err != nil {
+				log.Error().Err(err).Msg("Error starting controller")
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+
+		// Long-running mode is reconciled by a controller-runtime manager that
+		// watches Config CRs directly instead of polling them on a ticker.
+		if err := controllers.StartManager(ctx, k8sClient, cfgManager, envConf); err != nil {
+			log.Error().Err(err).Msg("Error starting controller-runtime manager")
 			os.Exit(1)
 		}
 		os.Exit(0)
 	}()
 
-	// go func() {
-	// 	if err := config.WatchConfig(ctx, cfgManager, "config.json"); err != nil {
-	// 		log.Error().Err(err).Msg("Error monitoring configuration")
-	// 	}
-	// }()
+	go func() {
+		if err := config.WatchConfigStore(ctx, cfgManager, k8sClient.GetDynamicClient()); err != nil {
+			log.Error().Err(err).Msg("Error watching Config/GitConfig store")
+		}
+	}()
+
+	// Tell systemd the unit is up once metrics.State reports ready (config
+	// loaded and no registered git remote unreachable), then keep it fed with
+	// watchdog heartbeats. Completion of the initial sync is not tracked: until
+	// the first Git client registers its remote, the ready check already
+	// passes. Both are no-ops when NOTIFY_SOCKET isn't set, e.g. under kubelet.
+	go health.NotifyWhenReady(ctx, metrics.State.Ready)
+	go health.StartWatchdog(ctx)
 
 	// Waiting for signal to terminate
 	<-sigs
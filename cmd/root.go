@@ -1,19 +1,42 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/RafOSS-br/K8sVersioner/config"
+	applog "github.com/RafOSS-br/K8sVersioner/log"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
+// Command groups, grouping the help output into the sections cobra renders
+// subcommands under.
+const (
+	managementGroupID = "management"
+	debugGroupID      = "debug"
+	otherGroupID      = "other"
+)
+
 var (
-	oneShot bool
+	oneShot     bool
+	metricsAddr string
+	logLevel    string
+	logFormat   string
+	logCaller   bool
+	verbosity   int
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "K8sVersioner",
 	Short: "K8sVersioner is a tool to manage Kubernetes resources versions",
 	Long:  `K8sVersioner is a tool to manage Kubernetes resources versions`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applog.Init(applog.Options{
+			Level:     resolveLogLevel(),
+			Format:    logFormat,
+			AddCaller: logCaller,
+		})
+	},
 }
 
 func Execute() {
@@ -23,9 +46,46 @@ func Execute() {
 }
 
 func init() {
-	// Add subcommands to the root command
+	rootCmd.AddGroup(
+		&cobra.Group{ID: managementGroupID, Title: "Management Commands:"},
+		&cobra.Group{ID: debugGroupID, Title: "Debug Commands:"},
+		&cobra.Group{ID: otherGroupID, Title: "Other Commands:"},
+	)
+	rootCmd.SetHelpCommandGroupID(otherGroupID)
+	rootCmd.SetCompletionCommandGroupID(otherGroupID)
+
+	// Add persistent flags shared by every subcommand
 	rootCmd.PersistentFlags().BoolVarP(&oneShot, "one-shot", "o", false, "Run the command only once")
-	rootCmd.AddCommand(kubeControllerSubCmd)
+	rootCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", ":9090", "Address the /metrics, /healthz, and /readyz HTTP server listens on")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", envOr("LOG_LEVEL", "info"), "Log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", envOr("LOG_FORMAT", "console"), "Log format: console (human-readable) or json")
+	rootCmd.PersistentFlags().BoolVar(&logCaller, "log-caller", false, "Include the call site (file:line) on every log line")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbosity", "v", "Increase log verbosity (-v=debug, -vv=trace); overrides --log-level")
+
+	// Add subcommands to the root command
+	rootCmd.AddCommand(kubeControllerSubCmd, initCmd, versionCmd)
+}
+
+// resolveLogLevel lets -v/--verbosity override whatever --log-level was set
+// to, since repeating -v is the more natural "just show me more" knob at the
+// command line.
+func resolveLogLevel() string {
+	switch {
+	case verbosity >= 2:
+		return "trace"
+	case verbosity == 1:
+		return "debug"
+	default:
+		return logLevel
+	}
+}
+
+// envOr returns the named environment variable's value, or def if it's unset.
+func envOr(name, def string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return def
 }
 
 func run(envConf *config.EnvironmentConfig, f func(*config.EnvironmentConfig)) {
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// version is overridden at build time via -ldflags "-X .../cmd.version=...".
+var version = "dev"
+
+var versionCmd = &cobra.Command{
+	Use:     "version",
+	Short:   "Print the K8sVersioner version",
+	GroupID: otherGroupID,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("K8sVersioner " + version)
+	},
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
 )
 
 const (
@@ -25,6 +30,10 @@ type ConfigManager struct {
 	cfg       []ConfigStore
 	gitMap    map[string]*GitConfig
 	configMap map[string]*Config
+
+	subMu       sync.Mutex
+	nextSubID   int
+	subscribers map[int]func()
 }
 
 func (cm *ConfigManager) GetGitMap() map[string]*GitConfig {
@@ -61,11 +70,12 @@ func (cm *ConfigManager) GetConfigMap() map[string]*Config {
 
 func NewConfigManager(cfg []ConfigStore) *ConfigManager {
 	return &ConfigManager{
-		cfg: cfg,
+		cfg:         cfg,
+		subscribers: make(map[int]func()),
 	}
 }
 
-func (cm *ConfigManager) Reload(path string, dynamicClient *dynamic.DynamicClient) error {
+func (cm *ConfigManager) Reload(dynamicClient *dynamic.DynamicClient) error {
 	cfg, err := LoadConfigStore(dynamicClient)
 	if err != nil {
 		return err
@@ -77,9 +87,43 @@ func (cm *ConfigManager) Reload(path string, dynamicClient *dynamic.DynamicClien
 	cm.configMap = nil
 	cm.mu.Unlock()
 
+	cm.notifySubscribers()
 	return nil
 }
 
+// Subscribe registers fn to run after every successful Reload, so
+// long-running watchers (see controllers.watchRunnable) can tear down and
+// rebuild themselves against the new Config/GitConfig set live instead of
+// only picking up changes on a process restart. It returns an unsubscribe
+// func.
+func (cm *ConfigManager) Subscribe(fn func()) (unsubscribe func()) {
+	cm.subMu.Lock()
+	defer cm.subMu.Unlock()
+
+	id := cm.nextSubID
+	cm.nextSubID++
+	cm.subscribers[id] = fn
+
+	return func() {
+		cm.subMu.Lock()
+		defer cm.subMu.Unlock()
+		delete(cm.subscribers, id)
+	}
+}
+
+func (cm *ConfigManager) notifySubscribers() {
+	cm.subMu.Lock()
+	fns := make([]func(), 0, len(cm.subscribers))
+	for _, fn := range cm.subscribers {
+		fns = append(fns, fn)
+	}
+	cm.subMu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
 type ConfigSpec struct {
 	Namespace       string            `json:"namespace" validate:"required"`                  // Namespace to watch
 	IncludeResource []ResourceFilter  `json:"includeResource,omitempty" validate:"dive"`      // Resources to include
@@ -87,14 +131,84 @@ type ConfigSpec struct {
 	OutputType      string            `json:"outputType" validate:"required,oneof=yaml json"` // Output type
 	Annotations     map[string]string `json:"annotations,omitempty"`                          // Annotation filters
 	GitRef          string            `json:"gitRef" validate:"required"`                     // Reference to GitConfig
-	KubeConfig      string            `json:"kubeConfig" validate:"required,file"`            // KubeConfig path
-	FolderStructure string            `json:"folderStructure" validate:"required"`            // Folder structure
+	KubeConfig      string            `json:"kubeConfig,omitempty"`                           // Name of a Secret (in this Config's own namespace) holding a "kubeconfig" blob for the target cluster; empty mirrors the management cluster
+	Cluster         string            `json:"cluster,omitempty"`                              // Cluster label, available as {{.Cluster}} in FolderStructure and included in commit/PR messages
+	FolderStructure string            `json:"folderStructure" validate:"required"`            // text/template rendered with {{.Cluster}}, {{.Namespace}}, {{.Kind}}, {{.Name}}, {{.APIVersion}}
+	Transforms      TransformsSpec    `json:"transforms,omitempty"`                           // Pipeline run over each resource before serialization
+}
+
+// TransformsSpec configures the pipeline run over each unstructured resource
+// before it is serialized and committed, mirroring what `kubectl neat` strips
+// by default so the first commit isn't polluted with server-managed noise.
+type TransformsSpec struct {
+	// StripFields lists dotted paths (e.g. "metadata.resourceVersion") to delete.
+	// When empty, a default set matching kubectl neat is used.
+	StripFields []string `json:"stripFields,omitempty"`
+	// RedactSecrets controls how v1/Secret data/stringData values are redacted.
+	RedactSecrets RedactSecretsSpec `json:"redactSecrets,omitempty"`
+	// JSONPatch applies an RFC6902/RFC7396 patch to resources matching a GVK.
+	JSONPatch []GVKPatch `json:"jsonPatch,omitempty"`
+}
+
+// RedactSecretsSpec controls whether v1/Secret values are replaced with a
+// SHA256 hash or the literal "***" so secrets can be versioned safely.
+type RedactSecretsSpec struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Mode    string `json:"mode,omitempty" validate:"omitempty,oneof=hash mask"` // "hash" or "mask" (default mask)
+}
+
+// GVKPatch is a JSON patch (RFC6902) or JSON merge patch (RFC7396) applied to
+// every resource of the given GroupVersionKind. Kubernetes strategic merge
+// patch semantics (patchMergeKey-aware list merging) are not implemented;
+// MergePatch is a plain RFC7396 merge, which is enough for map-shaped fields
+// but will replace rather than merge list fields like containers or ports.
+type GVKPatch struct {
+	APIVersion string `json:"apiVersion" validate:"required"`
+	Kind       string `json:"kind" validate:"required"`
+	Patch      []byte `json:"patch" validate:"required"` // RFC6902 JSON patch document
+	MergePatch []byte `json:"mergePatch,omitempty"`      // RFC7396 JSON merge patch document
 }
 
 type Config struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ConfigSpec `json:"spec,omitempty"`
+	Spec              ConfigSpec   `json:"spec,omitempty"`
+	Status            ConfigStatus `json:"status,omitempty"`
+}
+
+// ConfigStatus surfaces the outcome of the most recent sync so operators can
+// review cluster-state changes instead of only seeing direct pushes.
+type ConfigStatus struct {
+	// ObservedGeneration is the .metadata.generation the reconciler last acted on.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is when the reconciler last completed a sync attempt.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastCommitSHA is the commit produced by the last successful sync.
+	LastCommitSHA string `json:"lastCommitSHA,omitempty"`
+	// ResourceCount is how many resources were written in the last successful sync.
+	ResourceCount int `json:"resourceCount,omitempty"`
+
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+	PullRequestURL string             `json:"pullRequestUrl,omitempty"`
+}
+
+const (
+	ConditionPullRequestOpened = "PullRequestOpened"
+	ConditionReady             = "Ready"
+	ConditionGitReachable      = "GitReachable"
+	ConditionSyncSucceeded     = "SyncSucceeded"
+)
+
+// SetPullRequestCondition records that a sync produced a pull/merge request
+// instead of pushing directly, so the PR URL shows up on the Config CR.
+func SetPullRequestCondition(status *ConfigStatus, url string) {
+	status.PullRequestURL = url
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    ConditionPullRequestOpened,
+		Status:  metav1.ConditionTrue,
+		Reason:  "PullRequestCreated",
+		Message: url,
+	})
 }
 
 type ResourceFilter struct {
@@ -102,23 +216,54 @@ type ResourceFilter struct {
 	APIVersion        string `json:"apiVersion" validate:"required"` // API version of the resource
 	WithManagedFields bool   `json:"withManagedFields,omitempty"`    // Include managed fields
 	WithStatusField   bool   `json:"withStatusField,omitempty"`      // Include status field
+
+	DisableStripFields     bool `json:"disableStripFields,omitempty"`     // Skip the stripFields transform for this resource
+	DisableSecretRedaction bool `json:"disableSecretRedaction,omitempty"` // Skip the redactSecrets transform for this resource
+	DisablePatches         bool `json:"disablePatches,omitempty"`         // Skip the jsonPatch transform for this resource
 }
 
 type GitConfigSpec struct {
-	Protocol          string `json:"protocol" validate:"required,oneof=http https ssh"`               // Protocol
-	RepositoryURL     string `json:"repositoryUrl" validate:"required,url"`                           // Repository URL
-	Branch            string `json:"branch" validate:"required"`                                      // Branch
-	Username          string `json:"username,omitempty" validate:"required"`                          // Username (optional)
-	Password          string `json:"password,omitempty" validate:"required"`                          // Password (optional)
-	SSHPrivateKeyPath string `json:"sshPrivateKeyPath,omitempty" validate:"required_if=Protocol ssh"` // SSH Private Key Path
-	RepositoryPath    string `json:"repositoryPath" validate:"required"`                              // Repository Path
-	RepositoryFolder  string `json:"repositoryFolder" validate:"required"`                            // Repository Folder
-	DryRun            bool   `json:"dryRun,omitempty" validate:"required"`                            // Dry run mode
+	Protocol               string                 `json:"protocol" validate:"required,oneof=http https ssh"` // Protocol
+	RepositoryURL          string                 `json:"repositoryUrl" validate:"required,url"`             // Repository URL
+	Branch                 string                 `json:"branch" validate:"required"`                        // Branch
+	CredentialsSecretRef   corev1.SecretReference `json:"credentialsSecretRef" validate:"required"`          // Secret holding username/password, token, or ssh-privatekey(+ssh-passphrase) keys
+	KnownHostsConfigMapRef string                 `json:"knownHostsConfigMapRef,omitempty"`                  // ConfigMap holding known_hosts content used to verify the SSH host key
+	RepositoryPath         string                 `json:"repositoryPath" validate:"required"`                // Repository Path
+	RepositoryFolder       string                 `json:"repositoryFolder" validate:"required"`              // Repository Folder
+	DryRun                 bool                   `json:"dryRun,omitempty" validate:"required"`              // Dry run mode
+
+	Provider       string          `json:"provider,omitempty" validate:"omitempty,oneof=go-git github gitlab gitea"` // VCS provider backing PR/MR creation
+	APIURL         string          `json:"apiURL,omitempty" validate:"omitempty,url"`                                // Provider API base URL (self-hosted GitHub/GitLab/Gitea)
+	TokenSecretRef string          `json:"tokenSecretRef,omitempty"`                                                 // Secret holding the provider API token
+	BaseBranch     string          `json:"baseBranch,omitempty"`                                                     // Branch a pull/merge request targets, defaults to Branch
+	PullRequest    PullRequestSpec `json:"pullRequest,omitempty"`                                                    // Pull/merge request behavior
+}
+
+// PullRequestSpec controls whether sync commits are pushed straight to
+// Branch or routed through a pull/merge request for review.
+type PullRequestSpec struct {
+	Enabled       bool     `json:"enabled,omitempty"`       // Push to a sync branch and open a PR/MR instead of pushing to Branch directly
+	TitleTemplate string   `json:"titleTemplate,omitempty"` // text/template rendered with {{.Message}} (commit message) and {{.Branch}} (sync branch)
+	BodyTemplate  string   `json:"bodyTemplate,omitempty"`  // text/template rendered with {{.Message}} (commit message) and {{.Branch}} (sync branch)
+	Labels        []string `json:"labels,omitempty"`        // Labels applied to the created PR/MR
+	Assignees     []string `json:"assignees,omitempty"`     // Assignees applied to the created PR/MR
+	AutoMerge     bool     `json:"autoMerge,omitempty"`     // Enable provider auto-merge on the created PR/MR
 }
 
 type EnvironmentConfig struct {
 	OneShot       bool
 	ExecutionMode string `validate:"required,oneof=kube-controller standalone"`
+
+	// LeaderElection gates controller-runtime's built-in Lease-based election
+	// in controllers.StartManager: when true, only the replica holding the
+	// Lease runs the reconcile loops, so Deployments with replicas>1 don't
+	// duplicate commits to the target git repo.
+	LeaderElection bool
+	LeaseNamespace string
+	LeaseName      string
+	LeaseDuration  time.Duration
+	RenewDeadline  time.Duration
+	RetryPeriod    time.Duration
 }
 
 func (ec *EnvironmentConfig) Validate() error {
@@ -262,33 +407,60 @@ func LoadConfigStore(dynamicClient *dynamic.DynamicClient) ([]ConfigStore, error
 	return pairs, nil
 }
 
-// func WatchConfig(ctx context.Context, cfg *ConfigManager, path string, dynamicClient *dynamic.DynamicClient) error {
-// 	watcher, err := fsnotify.NewWatcher()
-// 	if err != nil {
-// 		return err
-// 	}
-// 	defer watcher.Close()
-
-// 	err = watcher.Add(path)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	for {
-// 		select {
-// 		case event := <-watcher.Events:
-// 			if event.Op&fsnotify.Write == fsnotify.Write {
-// 				if err := cfg.Reload(path, dynamicClient); err != nil {
-// 					if HandleValidationErrors(ctx, err) {
-// 						continue
-// 					}
-// 					log.Error().Err(err).Msg("Error reloading configuration")
-// 				}
-// 			}
-// 		case err := <-watcher.Errors:
-// 			log.Error().Err(err).Msg("Watcher error")
-// 		case <-ctx.Done():
-// 			return nil
-// 		}
-// 	}
-// }
+// configStoreDebounce is how long WatchConfigStore waits after the last
+// observed Config/GitConfig change before reloading, so a burst of CR edits
+// (e.g. applying a manifest with several Configs) collapses into one Reload.
+const configStoreDebounce = 2 * time.Second
+
+// WatchConfigStore watches the Config and GitConfig CRDs across all
+// namespaces and calls cm.Reload whenever one is added, updated, or
+// deleted, so the in-cluster config store picks up CR edits without a
+// process restart.
+func WatchConfigStore(ctx context.Context, cm *ConfigManager, dynamicClient *dynamic.DynamicClient) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, metav1.NamespaceAll, nil)
+
+	changed := make(chan struct{}, 1)
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notifyConfigChange(changed) },
+		UpdateFunc: func(_, _ interface{}) { notifyConfigChange(changed) },
+		DeleteFunc: func(interface{}) { notifyConfigChange(changed) },
+	}
+	factory.ForResource(ConfigGVR).Informer().AddEventHandler(handler)
+	factory.ForResource(GitConfigGVR).Informer().AddEventHandler(handler)
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	timer := time.NewTimer(configStoreDebounce)
+	defer timer.Stop()
+	if !timer.Stop() {
+		<-timer.C
+	}
+
+	for {
+		select {
+		case <-changed:
+			timer.Reset(configStoreDebounce)
+		case <-timer.C:
+			if err := cm.Reload(dynamicClient); err != nil {
+				if HandleValidationErrors(ctx, err) {
+					continue
+				}
+				log.Error().Err(err).Msg("Error reloading configuration")
+				continue
+			}
+			log.Info().Msg("Reloaded Config/GitConfig store after a CR change")
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// notifyConfigChange performs a non-blocking send so a burst of informer
+// events collapses into a single pending reload instead of queuing up.
+func notifyConfigChange(changed chan struct{}) {
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is group version used to register these types.
+var GroupVersion = schema.GroupVersion{Group: ResourceGroup, Version: ResourceVersion}
+
+// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
+var SchemeBuilder = &schemeBuilder{}
+
+// AddToScheme adds the Config and GitConfig types to the given scheme, as
+// required to run reconcilers through a controller-runtime manager.
+var AddToScheme = SchemeBuilder.AddToScheme
+
+type schemeBuilder struct{}
+
+func (s *schemeBuilder) AddToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion,
+		&Config{},
+		&ConfigList{},
+		&GitConfig{},
+		&GitConfigList{},
+	)
+	metav1.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
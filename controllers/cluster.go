@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+	k8s "github.com/RafOSS-br/K8sVersioner/kubernetes"
+
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+// clusterTarget bundles the dynamic client and REST mapper resolved for a
+// single cluster, so each mirrored cluster keeps its own discovery cache
+// instead of sharing one that only reflects the management cluster's API
+// surface.
+type clusterTarget struct {
+	dynClient dynamic.Interface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+}
+
+// clusterResolver resolves a Config's target cluster to a clusterTarget,
+// caching one per distinct KubeConfig secret so repeated Configs pointing at
+// the same remote cluster reuse its dynamic client and discovery cache.
+type clusterResolver struct {
+	mgmtClientset *clientgo.Clientset
+	defaultTarget clusterTarget
+
+	mu      sync.Mutex
+	targets map[string]clusterTarget
+}
+
+// newClusterResolver seeds the resolver with the management cluster's dynamic
+// client and mapper as the target used by Configs that leave KubeConfig empty.
+func newClusterResolver(mgmtClientset *clientgo.Clientset, dynClient dynamic.Interface, mapper *restmapper.DeferredDiscoveryRESTMapper) *clusterResolver {
+	return &clusterResolver{
+		mgmtClientset: mgmtClientset,
+		defaultTarget: clusterTarget{dynClient: dynClient, mapper: mapper},
+		targets:       make(map[string]clusterTarget),
+	}
+}
+
+// resolve returns the clusterTarget for cfg, building and caching a new
+// dynamic client/REST mapper pair from cfg.Spec.KubeConfig the first time a
+// given Secret is referenced.
+func (r *clusterResolver) resolve(ctx context.Context, cfg *config.Config) (clusterTarget, error) {
+	if cfg.Spec.KubeConfig == "" {
+		return r.defaultTarget, nil
+	}
+
+	key := cfg.Namespace + config.MapKeySeparator + cfg.Spec.KubeConfig
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if target, ok := r.targets[key]; ok {
+		return target, nil
+	}
+
+	remote, err := k8s.GetKubernetesConfigForSecret(ctx, r.mgmtClientset, cfg.Namespace, cfg.Spec.KubeConfig)
+	if err != nil {
+		return clusterTarget{}, fmt.Errorf("failed to resolve cluster for Config %q: %w", cfg.Name, err)
+	}
+
+	cachedDiscovery := memory.NewMemCacheClient(remote.GetClientset())
+	target := clusterTarget{
+		dynClient: remote.GetDynamicClient(),
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+	}
+	r.targets[key] = target
+	return target, nil
+}
+
+// clusterName returns cfg.Spec.Cluster, defaulting to "default" so path
+// templates and commit messages always have a stable value to render.
+func clusterName(cfg *config.Config) string {
+	if cfg.Spec.Cluster == "" {
+		return "default"
+	}
+	return cfg.Spec.Cluster
+}
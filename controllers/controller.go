@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"text/template"
 	"time"
 
 	"github.com/RafOSS-br/K8sVersioner/config"
 	"github.com/RafOSS-br/K8sVersioner/git"
 	"github.com/RafOSS-br/K8sVersioner/kubernetes"
+	"github.com/RafOSS-br/K8sVersioner/metrics"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/rs/zerolog/log"
@@ -18,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	clientgo "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/restmapper"
 )
 
@@ -45,32 +49,27 @@ func StartController(ctx context.Context, args ControllerArgs) error {
 
 	cachedDiscovery := memory.NewMemCacheClient(client)
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+	resolver := newClusterResolver(client, dynClient, mapper)
 
 	log.Info().Msg("Git client created successfully")
 	if env.OneShot {
-		if err := syncResources(ctx, cfgManager, dynClient, mapper); err != nil {
+		if err := syncResources(ctx, cfgManager, client, resolver); err != nil {
 			log.Error().Err(err).Msg("Error synchronizing resources")
 		}
 		return nil
 	}
-	// Main loop
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-	log.Info().Msg("Starting main controller loop")
-	for {
-		log.Info().Msg("Waiting for next synchronization cycle")
-		select {
-		case <-ticker.C:
-			if err := syncResources(ctx, cfgManager, dynClient, mapper); err != nil {
-				log.Error().Err(err).Msg("Error synchronizing resources")
-			}
-		case <-ctx.Done():
-			return nil
-		}
+
+	// Do an initial full sync so the repo reflects cluster state before the
+	// watch subsystem starts reacting to incremental events.
+	if err := syncResources(ctx, cfgManager, client, resolver); err != nil {
+		log.Error().Err(err).Msg("Error performing initial synchronization")
 	}
+
+	log.Info().Msg("Starting watch-based controller loop")
+	return startWatchers(ctx, cfgManager, client, resolver)
 }
 
-func syncResources(ctx context.Context, cfManager *config.ConfigManager, dynClient dynamic.Interface, mapper *restmapper.DeferredDiscoveryRESTMapper) error {
+func syncResources(ctx context.Context, cfManager *config.ConfigManager, clientset *clientgo.Clientset, resolver *clusterResolver) error {
 	log.Info().Msg("Starting resource synchronization")
 
 	cfgMap := cfManager.GetConfigMap()
@@ -87,16 +86,21 @@ func syncResources(ctx context.Context, cfManager *config.ConfigManager, dynClie
 			log.Error().Str("config", cfgStore.Name).Str("namespace", cfgStore.Namespace).Msg("Git configuration not found")
 			continue
 		}
-		gitClient, err := git.NewGitClient(ctx, gitConfig)
+		gitClient, err := git.NewGitClient(ctx, gitConfig, clientset)
 		if err != nil {
 			log.Error().Err(err).Msg("Error creating Git client")
 			continue
 		}
-		for _, resFilter := range cfgStore.Spec.IncludeResource {
-			if err := sync(ctx, cfgStore, resFilter, dynClient, mapper, gitClient); err != nil {
-				log.Error().Err(err).Msg("Error synchronizing resources")
-				continue
-			}
+
+		target, err := resolver.resolve(ctx, cfgStore)
+		if err != nil {
+			log.Error().Err(err).Str("config", cfgStore.Name).Msg("Error resolving target cluster")
+			continue
+		}
+
+		if _, err := syncConfig(ctx, cfgStore, target.dynClient, target.mapper, gitClient); err != nil {
+			log.Error().Err(err).Msg("Error synchronizing resources")
+			continue
 		}
 	}
 
@@ -104,116 +108,160 @@ func syncResources(ctx context.Context, cfManager *config.ConfigManager, dynClie
 	return nil
 }
 
-// sync synchronizes Kubernetes resources based on the provided configuration
-func sync(ctx context.Context, cfg *config.Config, resFilter config.ResourceFilter, dynClient dynamic.Interface, mapper *restmapper.DeferredDiscoveryRESTMapper, gitClient *git.GitClient) error {
+// syncConfig writes resources for every configured ResourceFilter and then
+// commits and pushes the accumulated changes as a single commit (and, if
+// PullRequest is enabled, a single PR) per sync run, instead of one commit
+// per filter. It returns how many resources were written, for status
+// reporting.
+func syncConfig(ctx context.Context, cfg *config.Config, dynClient dynamic.Interface, mapper *restmapper.DeferredDiscoveryRESTMapper, gitClient *git.GitClient) (int, error) {
+	start := time.Now()
+	defer func() {
+		metrics.SyncDuration.WithLabelValues(cfg.Name).Observe(time.Since(start).Seconds())
+	}()
+
+	written := 0
+	for _, resFilter := range cfg.Spec.IncludeResource {
+		n, err := syncFilter(ctx, cfg, resFilter, dynClient, mapper, gitClient)
+		written += n
+		if err != nil {
+			metrics.SyncTotal.WithLabelValues(cfg.Name, "failure").Inc()
+			return written, err
+		}
+	}
+
+	if written > 0 {
+		message := fmt.Sprintf("Resource synchronization on %s (cluster=%s)", time.Now().Format(time.RFC3339), clusterName(cfg))
+		if err := gitClient.CommitAndPush(ctx, message); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Error committing and pushing to Git")
+			metrics.SyncTotal.WithLabelValues(cfg.Name, "failure").Inc()
+			return written, err
+		}
+
+		if prURL := gitClient.LastPullRequestURL(); prURL != "" {
+			config.SetPullRequestCondition(&cfg.Status, prURL)
+			log.Info().Str("url", prURL).Msg("Opened pull request for cluster-state changes")
+		}
+	}
+
+	metrics.SyncTotal.WithLabelValues(cfg.Name, "success").Inc()
+	metrics.LastSuccessfulSync.WithLabelValues(cfg.Name).Set(float64(time.Now().Unix()))
+	return written, nil
+}
+
+// syncFilter lists and writes the resources matching a single ResourceFilter
+// to the git worktree, without committing. The caller is responsible for
+// committing and pushing once all of a Config's filters have been written.
+func syncFilter(ctx context.Context, cfg *config.Config, resFilter config.ResourceFilter, dynClient dynamic.Interface, mapper *restmapper.DeferredDiscoveryRESTMapper, gitClient *git.GitClient) (int, error) {
 	// Determine namespaces to process
 	namespaces, err := determineNamespaces(ctx, cfg.Namespace, dynClient)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to determine namespaces")
-		return err
+		return 0, err
 	}
 
-	var gvkList []schema.GroupVersionKind
+	written := 0
 
-	// Specific GroupVersionKind
 	gvk := schema.FromAPIVersionAndKind(resFilter.APIVersion, resFilter.Name)
-	gvkList = append(gvkList, gvk)
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("kind", gvk.Kind).
+			Msg("Error getting REST mapping")
+		return 0, nil
+	}
+
+	for _, namespace := range namespaces {
+		resourceClient := dynClient.Resource(mapping.Resource).Namespace(namespace)
 
-	for _, gvk := range gvkList {
-		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		list, err := resourceClient.List(ctx, v1.ListOptions{})
 		if err != nil {
 			log.Error().
 				Err(err).
-				Str("kind", gvk.Kind).
-				Msg("Error getting REST mapping")
+				Str("resource", mapping.Resource.Resource).
+				Str("namespace", namespace).
+				Msg("Error listing resources")
 			continue
 		}
 
-		for _, namespace := range namespaces {
-			resourceClient := dynClient.Resource(mapping.Resource).Namespace(namespace)
+		for _, item := range list.Items {
+			// Apply label and annotation filters if necessary
+			if !matchFilters(&item, cfg.Labels, cfg.Annotations) {
+				continue
+			}
 
-			list, err := resourceClient.List(ctx, v1.ListOptions{})
-			if err != nil {
+			var data []byte
+
+			// Remove managed fields if not required
+			if !resFilter.WithManagedFields {
+				item.SetManagedFields(nil)
+			}
+
+			// Remove status field if not required
+			if !resFilter.WithStatusField {
+				delete(item.Object, "status")
+			}
+
+			// Run the configured transforms pipeline (field stripping,
+			// secret redaction, GVK patches) before serialization
+			if err := applyTransforms(cfg, resFilter, &item); err != nil {
 				log.Error().
 					Err(err).
 					Str("resource", mapping.Resource.Resource).
-					Str("namespace", namespace).
-					Msg("Error listing resources")
+					Str("name", item.GetName()).
+					Msg("Error applying transforms")
 				continue
 			}
 
-			for _, item := range list.Items {
-				// Apply label and annotation filters if necessary
-				if !matchFilters(&item, cfg.Labels, cfg.Annotations) {
+			// Serialize the resource
+			if cfg.Spec.OutputType == "yaml" {
+				data, err = yaml.Marshal(item.Object)
+				if err != nil {
+					log.Error().
+						Err(err).
+						Str("resource", mapping.Resource.Resource).
+						Str("name", item.GetName()).
+						Msg("Error serializing the resource to YAML")
 					continue
 				}
-
-				var data []byte
-
-				// Remove managed fields if not required
-				if !resFilter.WithManagedFields {
-					item.SetManagedFields(nil)
-				}
-
-				// Remove status field if not required
-				if !resFilter.WithStatusField {
-					delete(item.Object, "status")
-				}
-
-				// Serialize the resource
-				if cfg.Spec.OutputType == "yaml" {
-					data, err = yaml.Marshal(item.Object)
-					if err != nil {
-						log.Error().
-							Err(err).
-							Str("resource", mapping.Resource.Resource).
-							Str("name", item.GetName()).
-							Msg("Error serializing the resource to YAML")
-						continue
-					}
-				} else {
-					data, err = json.MarshalIndent(item.Object, "", "  ")
-					if err != nil {
-						log.Error().
-							Err(err).
-							Str("resource", mapping.Resource.Resource).
-							Str("name", item.GetName()).
-							Msg("Error serializing the resource to JSON")
-						continue
-					}
-				}
-
-				// Generate file path based on folder structure
-				path := generateFilePath(cfg.Spec.FolderStructure, &item)
-
-				// Save the resource to Git
-				if err := gitClient.SaveResource(ctx, path, data); err != nil {
+			} else {
+				data, err = json.MarshalIndent(item.Object, "", "  ")
+				if err != nil {
 					log.Error().
 						Err(err).
-						Str("path", path).
-						Msg("Error saving the resource to Git")
+						Str("resource", mapping.Resource.Resource).
+						Str("name", item.GetName()).
+						Msg("Error serializing the resource to JSON")
 					continue
 				}
+			}
 
-				log.Info().
-					Str("resource", mapping.Resource.Resource).
-					Str("name", item.GetName()).
-					Str("namespace", item.GetNamespace()).
+			// Generate file path based on folder structure
+			path := generateFilePath(cfg.Spec.FolderStructure, clusterName(cfg), &item)
+
+			// Save the resource to Git
+			if err := gitClient.SaveResource(ctx, path, data); err != nil {
+				log.Error().
+					Err(err).
 					Str("path", path).
-					Msg("Resource saved to Git")
+					Msg("Error saving the resource to Git")
+				continue
 			}
+
+			log.Info().
+				Str("resource", mapping.Resource.Resource).
+				Str("name", item.GetName()).
+				Str("namespace", item.GetNamespace()).
+				Str("path", path).
+				Msg("Resource saved to Git")
+			written++
+			metrics.ResourcesWritten.WithLabelValues(gvk.String(), item.GetNamespace()).Inc()
 		}
 	}
 
-	// Commit and push the changes
-	message := fmt.Sprintf("Resource synchronization on %s", time.Now().Format(time.RFC3339))
-	if err := gitClient.CommitAndPush(ctx, message); err != nil {
-		log.Error().
-			Err(err).
-			Msg("Error committing and pushing to Git")
-		return err
-	}
-	return nil
+	return written, nil
 }
 
 // determineNamespaces determines the list of namespaces to process based on the configuration
@@ -256,14 +304,47 @@ func matchFilters(_ *unstructured.Unstructured, _, _ map[string]string) bool {
 	return true
 }
 
-// generateFilePath(structure string, item *unstructured.Unstructured) string
-func generateFilePath(_ string, item *unstructured.Unstructured) string {
-	// Simple example of path generation
+// generateFilePath renders structure as a text/template with Cluster,
+// Namespace, Kind, Name and APIVersion fields, falling back to a fixed
+// <cluster>/<namespace>/<kind>/<name>.yaml layout if structure is empty,
+// fails to parse, or fails to execute.
+func generateFilePath(structure, cluster string, item *unstructured.Unstructured) string {
 	namespace := item.GetNamespace()
 	if namespace == "" {
 		namespace = "all"
 	}
-	resourceType := item.GetKind()
-	resourceName := item.GetName()
-	return fmt.Sprintf("%s/%s/%s.yaml", namespace, resourceType, resourceName)
+
+	data := struct {
+		Cluster    string
+		Namespace  string
+		Kind       string
+		Name       string
+		APIVersion string
+	}{
+		Cluster:    cluster,
+		Namespace:  namespace,
+		Kind:       item.GetKind(),
+		Name:       item.GetName(),
+		APIVersion: item.GetAPIVersion(),
+	}
+
+	fallback := fmt.Sprintf("%s/%s/%s/%s.yaml", data.Cluster, data.Namespace, data.Kind, data.Name)
+
+	if structure == "" {
+		return fallback
+	}
+
+	tmpl, err := template.New("folderStructure").Parse(structure)
+	if err != nil {
+		log.Error().Err(err).Str("structure", structure).Msg("Invalid folder structure template, falling back to default path")
+		return fallback
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		log.Error().Err(err).Str("structure", structure).Msg("Failed to render folder structure template, falling back to default path")
+		return fallback
+	}
+
+	return buf.String()
 }
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+	"github.com/RafOSS-br/K8sVersioner/git"
+	"github.com/RafOSS-br/K8sVersioner/kubernetes"
+	applog "github.com/RafOSS-br/K8sVersioner/log"
+
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
+	clientgoKubernetes "k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// runtimeScheme builds the scheme the manager's client needs to know about
+// both built-in Kubernetes types and K8sVersioner's Config/GitConfig CRs.
+func runtimeScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = config.AddToScheme(scheme)
+	return scheme
+}
+
+// ConfigReconciler drives a single Config CR to its desired git-mirrored
+// state and reports the outcome on .status, replacing the old
+// unstructured-list-every-5-minutes polling with level-triggered
+// reconciliation.
+type ConfigReconciler struct {
+	client.Client
+	Clientset  *clientgoKubernetes.Clientset
+	Resolver   *clusterResolver
+	CfgManager *config.ConfigManager
+	Recorder   record.EventRecorder
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *ConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = applog.WithValues(ctx, "resource", req.Name, "namespace", req.Namespace)
+
+	var cfg config.Config
+	if err := r.Get(ctx, req.NamespacedName, &cfg); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get Config %s: %w", req.NamespacedName, err)
+	}
+
+	gitMap := r.CfgManager.GetGitMap()
+	gitCfg, ok := gitMap[cfg.Spec.GitRef+config.MapKeySeparator+cfg.Namespace]
+	if !ok {
+		r.setCondition(&cfg, config.ConditionGitReachable, metav1.ConditionFalse, "GitConfigNotFound", fmt.Sprintf("GitConfig %q not found", cfg.Spec.GitRef))
+		r.Recorder.Eventf(&cfg, "Warning", "GitConfigNotFound", "GitConfig %q referenced by Config %q not found", cfg.Spec.GitRef, cfg.Name)
+		return ctrl.Result{}, r.updateStatus(ctx, &cfg)
+	}
+
+	gitClient, err := git.NewGitClient(ctx, gitCfg, r.Clientset)
+	if err != nil {
+		r.setCondition(&cfg, config.ConditionGitReachable, metav1.ConditionFalse, "GitClientError", err.Error())
+		r.Recorder.Eventf(&cfg, "Warning", "SyncFailed", "Failed to create Git client: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, &cfg)
+	}
+	r.setCondition(&cfg, config.ConditionGitReachable, metav1.ConditionTrue, "GitClientReady", "Git remote reachable")
+
+	target, err := r.Resolver.resolve(ctx, &cfg)
+	if err != nil {
+		r.setCondition(&cfg, config.ConditionGitReachable, metav1.ConditionFalse, "ClusterResolveError", err.Error())
+		r.Recorder.Eventf(&cfg, "Warning", "SyncFailed", "Failed to resolve target cluster: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, &cfg)
+	}
+
+	// This runs once per Config spec change (add/update), to seed the repo
+	// with current cluster state before watchRunnable's per-resource
+	// informers take over (see watch.go). It deliberately does not requeue:
+	// requeuing here would reintroduce the old fixed-interval full re-dump,
+	// and it never mirrors deletions, which only the watch subsystem does.
+	// Ongoing drift is handled by watched events, not by polling.
+	count, err := syncConfig(ctx, &cfg, target.dynClient, target.mapper, gitClient)
+	if err != nil {
+		r.setCondition(&cfg, config.ConditionSyncSucceeded, metav1.ConditionFalse, "SyncError", err.Error())
+		r.Recorder.Eventf(&cfg, "Warning", "SyncFailed", "Error synchronizing resources: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, &cfg)
+	}
+
+	now := metav1.Now()
+	cfg.Status.ObservedGeneration = cfg.Generation
+	cfg.Status.LastSyncTime = &now
+	cfg.Status.ResourceCount = count
+	cfg.Status.LastCommitSHA = gitClient.LastCommitSHA()
+	r.setCondition(&cfg, config.ConditionSyncSucceeded, metav1.ConditionTrue, "SyncComplete", "Resources synchronized successfully")
+	r.setCondition(&cfg, config.ConditionReady, metav1.ConditionTrue, "Ready", "Config is synchronizing successfully")
+	r.Recorder.Eventf(&cfg, "Normal", "SyncSucceeded", "Synchronized %d resources", count)
+
+	ctx = applog.WithValues(ctx, "commit", cfg.Status.LastCommitSHA)
+	return ctrl.Result{}, r.updateStatus(ctx, &cfg)
+}
+
+func (r *ConfigReconciler) setCondition(cfg *config.Config, condType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&cfg.Status.Conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cfg.Generation,
+	})
+}
+
+func (r *ConfigReconciler) updateStatus(ctx context.Context, cfg *config.Config) error {
+	if err := r.Status().Update(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to update Config status: %w", err)
+	}
+	applog.FromContext(ctx).Debug().Msg("Updated Config status")
+	return nil
+}
+
+// SetupWithManager registers the reconciler with a controller-runtime
+// manager so it watches Config CRs instead of being polled from a ticker.
+// Only generation changes (spec edits) and creations trigger a reconcile, so
+// the reconciler's own status updates don't re-trigger a sync.
+func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&config.Config{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
+
+// StartManager runs a controller-runtime manager that reconciles Config CRs
+// directly, replacing the unstructured-list-every-5-minutes polling loop
+// with level-triggered reconciliation and typed status conditions. When
+// env.LeaderElection is set, the manager's built-in Lease-based election
+// gates reconciliation so multiple replicas don't duplicate commits to the
+// target git repo.
+func StartManager(ctx context.Context, k8sClient *kubernetes.K8sClient, cfgManager *config.ConfigManager, env *config.EnvironmentConfig) error {
+	scheme := runtimeScheme()
+
+	opts := ctrl.Options{Scheme: scheme}
+	if env != nil && env.LeaderElection {
+		opts.LeaderElection = true
+		opts.LeaderElectionID = env.LeaseName
+		opts.LeaderElectionNamespace = env.LeaseNamespace
+		if env.LeaseDuration > 0 {
+			opts.LeaseDuration = &env.LeaseDuration
+		}
+		if env.RenewDeadline > 0 {
+			opts.RenewDeadline = &env.RenewDeadline
+		}
+		if env.RetryPeriod > 0 {
+			opts.RetryPeriod = &env.RetryPeriod
+		}
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to create controller-runtime manager: %w", err)
+	}
+
+	client := k8sClient.GetClientset()
+	cachedDiscovery := memory.NewMemCacheClient(client)
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+	resolver := newClusterResolver(client, k8sClient.GetDynamicClient(), mapper)
+
+	reconciler := &ConfigReconciler{
+		Client:     mgr.GetClient(),
+		Clientset:  client,
+		Resolver:   resolver,
+		CfgManager: cfgManager,
+		Recorder:   mgr.GetEventRecorderFor("k8sversioner"),
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed to set up Config reconciler: %w", err)
+	}
+
+	// The informer/debounce subsystem in watch.go drives near-real-time
+	// per-resource commits on top of the level-triggered reconciler above;
+	// register it as a manager Runnable so it actually runs in this process.
+	if err := mgr.Add(&watchRunnable{cfgManager: cfgManager, clientset: client, resolver: resolver}); err != nil {
+		return fmt.Errorf("failed to register watch-based sync runnable: %w", err)
+	}
+
+	log.Info().Msg("Starting controller-runtime manager")
+	return mgr.Start(ctx)
+}
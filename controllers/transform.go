@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultStripFields mirrors what `kubectl neat` strips by default, so the
+// first commit of a resource isn't polluted with server-managed noise.
+func defaultStripFields() []string {
+	return []string{
+		"metadata.resourceVersion",
+		"metadata.uid",
+		"metadata.creationTimestamp",
+		"metadata.generation",
+		"metadata.ownerReferences",
+		"metadata.selfLink",
+	}
+}
+
+// applyTransforms runs the configured transforms pipeline over item before
+// serialization: stripping noisy fields, redacting Secret values, and
+// applying any per-GVK JSON/merge patches. Each stage is independently
+// toggleable via ResourceFilter.
+func applyTransforms(cfg *config.Config, resFilter config.ResourceFilter, item *unstructured.Unstructured) error {
+	if !resFilter.DisableStripFields {
+		stripFields(item, stripFieldPaths(cfg.Spec.Transforms))
+	}
+
+	if !resFilter.DisableSecretRedaction && cfg.Spec.Transforms.RedactSecrets.Enabled && item.GetKind() == "Secret" {
+		redactSecret(item, cfg.Spec.Transforms.RedactSecrets.Mode)
+	}
+
+	if !resFilter.DisablePatches && len(cfg.Spec.Transforms.JSONPatch) > 0 {
+		if err := applyGVKPatches(item, cfg.Spec.Transforms.JSONPatch); err != nil {
+			return fmt.Errorf("failed to apply patch to %s/%s: %w", item.GetKind(), item.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+func stripFieldPaths(t config.TransformsSpec) []string {
+	if len(t.StripFields) > 0 {
+		return t.StripFields
+	}
+	return defaultStripFields()
+}
+
+func stripFields(item *unstructured.Unstructured, paths []string) {
+	for _, path := range paths {
+		unstructured.RemoveNestedField(item.Object, strings.Split(path, ".")...)
+	}
+}
+
+// redactSecret replaces v1/Secret data/stringData values with a SHA256 hash
+// or the literal "***" so secrets can be versioned without leaking them.
+func redactSecret(item *unstructured.Unstructured, mode string) {
+	redactField(item, "data", mode)
+	redactField(item, "stringData", mode)
+}
+
+func redactField(item *unstructured.Unstructured, field, mode string) {
+	values, found, err := unstructured.NestedMap(item.Object, field)
+	if err != nil || !found {
+		return
+	}
+	for k, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if mode == "hash" {
+			sum := sha256.Sum256([]byte(s))
+			values[k] = hex.EncodeToString(sum[:])
+		} else {
+			values[k] = "***"
+		}
+	}
+	_ = unstructured.SetNestedMap(item.Object, values, field)
+}
+
+// applyGVKPatches applies every GVKPatch matching item's GroupVersionKind, in
+// order, each as a JSON patch (RFC6902) or JSON merge patch (RFC7396).
+func applyGVKPatches(item *unstructured.Unstructured, patches []config.GVKPatch) error {
+	gvk := item.GroupVersionKind()
+
+	for _, p := range patches {
+		if schema.FromAPIVersionAndKind(p.APIVersion, p.Kind) != gvk {
+			continue
+		}
+
+		original, err := json.Marshal(item.Object)
+		if err != nil {
+			return err
+		}
+
+		var modified []byte
+		switch {
+		case len(p.Patch) > 0:
+			patch, err := jsonpatch.DecodePatch(p.Patch)
+			if err != nil {
+				return err
+			}
+			modified, err = patch.Apply(original)
+			if err != nil {
+				return err
+			}
+		case len(p.MergePatch) > 0:
+			modified, err = jsonpatch.MergePatch(original, p.MergePatch)
+			if err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal(modified, &obj); err != nil {
+			return err
+		}
+		item.Object = obj
+	}
+
+	return nil
+}
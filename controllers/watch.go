@@ -0,0 +1,357 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+	"github.com/RafOSS-br/K8sVersioner/git"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	clientgo "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// debounceWindow is how long a GitConfig's batcher waits after the last
+// received event before flushing it as a single commit.
+const debounceWindow = 5 * time.Second
+
+// resourceEvent describes a single Add/Update/Delete observed by an informer.
+type resourceEvent struct {
+	eventType string // "added", "modified", "deleted"
+	cfg       *config.Config
+	resFilter config.ResourceFilter
+	item      *unstructured.Unstructured
+	path      string
+}
+
+// startWatchers replaces the polling loop with one dynamic informer per
+// GroupVersionKind/namespace pair and a debounced batch committer per
+// GitConfig, so cluster events drive commits directly instead of a full
+// periodic re-dump.
+func startWatchers(ctx context.Context, cfgManager *config.ConfigManager, clientset *clientgo.Clientset, resolver *clusterResolver) error {
+	batchers := map[string]*gitBatcher{}
+	var wg sync.WaitGroup
+
+	cfgMap := cfgManager.GetConfigMap()
+	gitMap := cfgManager.GetGitMap()
+
+	for _, cfgStore := range cfgMap {
+		gitConfig, ok := gitMap[cfgStore.Spec.GitRef+config.MapKeySeparator+cfgStore.Namespace]
+		if !ok {
+			log.Error().Str("config", cfgStore.Name).Msg("Git configuration not found")
+			continue
+		}
+
+		target, err := resolver.resolve(ctx, cfgStore)
+		if err != nil {
+			log.Error().Err(err).Str("config", cfgStore.Name).Msg("Error resolving target cluster")
+			continue
+		}
+
+		gitKey := gitConfig.Name + config.MapKeySeparator + gitConfig.Namespace
+		batcher, ok := batchers[gitKey]
+		if !ok {
+			gitClient, err := git.NewGitClient(ctx, gitConfig, clientset)
+			if err != nil {
+				log.Error().Err(err).Msg("Error creating Git client")
+				continue
+			}
+			batcher = newGitBatcher(gitClient)
+			batchers[gitKey] = batcher
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				batcher.run(ctx)
+			}()
+		}
+
+		namespaces, err := determineNamespaces(ctx, cfgStore.Namespace, target.dynClient)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to determine namespaces")
+			continue
+		}
+
+		for _, resFilter := range cfgStore.Spec.IncludeResource {
+			gvk := schema.FromAPIVersionAndKind(resFilter.APIVersion, resFilter.Name)
+			mapping, err := target.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				log.Error().Err(err).Str("kind", gvk.Kind).Msg("Error getting REST mapping")
+				continue
+			}
+
+			for _, namespace := range namespaces {
+				queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "k8sversioner")
+				factory := dynamicinformer.NewFilteredDynamicInformerFactory(target.dynClient, 0, namespace, nil)
+				informer := factory.ForResource(mapping.Resource).Informer()
+
+				cs, rf := cfgStore, resFilter
+				informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+					AddFunc: func(obj interface{}) {
+						enqueue(queue, "added", cs, rf, obj)
+					},
+					UpdateFunc: func(_, obj interface{}) {
+						enqueue(queue, "modified", cs, rf, obj)
+					},
+					DeleteFunc: func(obj interface{}) {
+						enqueue(queue, "deleted", cs, rf, obj)
+					},
+				})
+
+				factory.Start(ctx.Done())
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					runEventWorker(ctx, queue, batcher)
+				}()
+			}
+		}
+	}
+
+	<-ctx.Done()
+	for _, b := range batchers {
+		b.stop()
+	}
+	wg.Wait()
+	return nil
+}
+
+// enqueue wraps the informer callback payload and pushes it onto the
+// per-resource work queue for processing by runEventWorker.
+func enqueue(queue workqueue.RateLimitingInterface, eventType string, cfgStore *config.Config, resFilter config.ResourceFilter, obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			u, ok = tombstone.Obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+		} else {
+			return
+		}
+	}
+
+	evt := &resourceEvent{
+		eventType: eventType,
+		cfg:       cfgStore,
+		resFilter: resFilter,
+		item:      u.DeepCopy(),
+	}
+	queue.Add(evt)
+}
+
+// runEventWorker drains the work queue, normalizes each resource and hands
+// it to the batcher responsible for the owning GitConfig.
+func runEventWorker(ctx context.Context, queue workqueue.RateLimitingInterface, batcher *gitBatcher) {
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+
+	for {
+		item, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+
+		evt := item.(*resourceEvent)
+		if err := processEvent(evt); err != nil {
+			log.Error().Err(err).Str("event", evt.eventType).Msg("Error processing resource event")
+			queue.Forget(item)
+			queue.Done(item)
+			continue
+		}
+
+		batcher.add(evt)
+		queue.Forget(item)
+		queue.Done(item)
+	}
+}
+
+// processEvent applies the configured field/status trimming and, for
+// non-delete events, the transforms pipeline, mutating evt.item in place and
+// filling in evt.path. Serialization happens later, in gitBatcher.flush.
+func processEvent(evt *resourceEvent) error {
+	if !evt.resFilter.WithManagedFields {
+		evt.item.SetManagedFields(nil)
+	}
+	if !evt.resFilter.WithStatusField {
+		delete(evt.item.Object, "status")
+	}
+	if evt.eventType != "deleted" {
+		if err := applyTransforms(evt.cfg, evt.resFilter, evt.item); err != nil {
+			return err
+		}
+	}
+	evt.path = generateFilePath(evt.cfg.Spec.FolderStructure, clusterName(evt.cfg), evt.item)
+	return nil
+}
+
+// gitBatcher accumulates resourceEvents for a single GitConfig and flushes
+// them as one commit after debounceWindow of inactivity.
+type gitBatcher struct {
+	gitClient *git.GitClient
+	events    chan *resourceEvent
+	done      chan struct{}
+}
+
+func newGitBatcher(gitClient *git.GitClient) *gitBatcher {
+	return &gitBatcher{
+		gitClient: gitClient,
+		events:    make(chan *resourceEvent, 256),
+		done:      make(chan struct{}),
+	}
+}
+
+func (b *gitBatcher) add(evt *resourceEvent) {
+	b.events <- evt
+}
+
+func (b *gitBatcher) stop() {
+	close(b.done)
+}
+
+func (b *gitBatcher) run(ctx context.Context) {
+	var pending []*resourceEvent
+	timer := time.NewTimer(debounceWindow)
+	defer timer.Stop()
+	if !timer.Stop() {
+		<-timer.C
+	}
+
+	for {
+		select {
+		case evt := <-b.events:
+			pending = append(pending, evt)
+			timer.Reset(debounceWindow)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			b.flush(ctx, pending)
+			pending = nil
+		case <-b.done:
+			if len(pending) > 0 {
+				// ctx is already canceled by the time startWatchers calls
+				// stop() (it does so after <-ctx.Done()), so flushing with it
+				// would hand CommitAndPush an already-dead context and fail
+				// the push. Use a fresh context for this final flush instead.
+				b.flush(context.Background(), pending)
+			}
+			return
+		}
+	}
+}
+
+// flush writes and/or deletes the files for a batch of events and produces
+// a single commit summarizing the changed kinds and names.
+func (b *gitBatcher) flush(ctx context.Context, batch []*resourceEvent) {
+	var summary []string
+
+	for _, evt := range batch {
+		if evt.eventType == "deleted" {
+			if err := b.gitClient.DeleteResource(ctx, evt.path); err != nil {
+				log.Error().Err(err).Str("path", evt.path).Msg("Error deleting resource from Git")
+				continue
+			}
+		} else {
+			data, err := serializeResource(evt.cfg.Spec.OutputType, evt.item)
+			if err != nil {
+				log.Error().Err(err).Str("path", evt.path).Msg("Error serializing resource")
+				continue
+			}
+			if err := b.gitClient.SaveResource(ctx, evt.path, data); err != nil {
+				log.Error().Err(err).Str("path", evt.path).Msg("Error saving resource to Git")
+				continue
+			}
+		}
+		summary = append(summary, fmt.Sprintf("%s %s/%s (cluster=%s)", evt.eventType, evt.item.GetKind(), evt.item.GetName(), clusterName(evt.cfg)))
+	}
+
+	if len(summary) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Sync: %s", strings.Join(summary, ", "))
+	if err := b.gitClient.CommitAndPush(ctx, message); err != nil {
+		log.Error().Err(err).Msg("Error committing and pushing batched changes")
+	} else if prURL := b.gitClient.LastPullRequestURL(); prURL != "" {
+		seen := map[string]bool{}
+		for _, evt := range batch {
+			if seen[evt.cfg.Name] {
+				continue
+			}
+			seen[evt.cfg.Name] = true
+			config.SetPullRequestCondition(&evt.cfg.Status, prURL)
+		}
+	}
+}
+
+func serializeResource(outputType string, item *unstructured.Unstructured) ([]byte, error) {
+	if outputType == "yaml" {
+		return yaml.Marshal(item.Object)
+	}
+	return json.MarshalIndent(item.Object, "", "  ")
+}
+
+// watchRunnable adapts startWatchers to a controller-runtime manager.Runnable
+// so the informer/debounce subsystem actually runs as part of the process
+// StartManager drives, instead of only being reachable from StartController's
+// long-running branch, which kubeController never takes (it calls
+// StartController only in one-shot mode). It is gated on leader election like
+// every other write path here, so standby replicas don't also commit.
+type watchRunnable struct {
+	cfgManager *config.ConfigManager
+	clientset  *clientgo.Clientset
+	resolver   *clusterResolver
+}
+
+// Start runs startWatchers against the ConfigManager's current Config/GitConfig
+// set, and rebuilds it from scratch whenever the ConfigManager reloads, so
+// Configs added or removed via the CRD store take effect without a process
+// restart instead of only being picked up at startup.
+func (w *watchRunnable) Start(ctx context.Context) error {
+	changed := make(chan struct{}, 1)
+	unsubscribe := w.cfgManager.Subscribe(func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	for {
+		genCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- startWatchers(genCtx, w.cfgManager, w.clientset, w.resolver)
+		}()
+
+		select {
+		case <-changed:
+			cancel()
+			<-done
+		case err := <-done:
+			cancel()
+			return err
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		}
+	}
+}
+
+func (w *watchRunnable) NeedLeaderElection() bool {
+	return true
+}
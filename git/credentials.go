@@ -0,0 +1,147 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// credentials holds what was loaded from CredentialsSecretRef, plus enough
+// bookkeeping to detect rotation via the Secret's resourceVersion.
+type credentials struct {
+	auth            transport.AuthMethod
+	providerToken   string
+	resourceVersion string
+}
+
+// loadCredentials fetches the Secret referenced by CredentialsSecretRef and
+// builds the matching auth method, supporting the same username/password,
+// token, and ssh-privatekey(+ssh-passphrase) key conventions as a Flux
+// GitRepository source.
+func loadCredentials(ctx context.Context, clientset *kubernetes.Clientset, cfg *config.GitConfig) (*credentials, error) {
+	ref := cfg.Spec.CredentialsSecretRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = cfg.Namespace
+	}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	auth, providerToken, err := authFromSecret(ctx, clientset, cfg, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Spec.TokenSecretRef != "" {
+		providerToken, err = loadProviderToken(ctx, clientset, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &credentials{
+		auth:            auth,
+		providerToken:   providerToken,
+		resourceVersion: secret.ResourceVersion,
+	}, nil
+}
+
+// loadProviderToken reads the VCS provider API token from TokenSecretRef, so
+// ssh-protocol repos (whose ssh key carries no provider token) can still
+// authenticate PR/MR creation against GitHub/GitLab/Gitea.
+func loadProviderToken(ctx context.Context, clientset *kubernetes.Clientset, cfg *config.GitConfig) (string, error) {
+	secret, err := clientset.CoreV1().Secrets(cfg.Namespace).Get(ctx, cfg.Spec.TokenSecretRef, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to load provider token secret %s/%s: %w", cfg.Namespace, cfg.Spec.TokenSecretRef, err)
+	}
+	token, ok := secret.Data["token"]
+	if !ok {
+		return "", fmt.Errorf("provider token secret %s/%s has no token key", cfg.Namespace, cfg.Spec.TokenSecretRef)
+	}
+	return string(token), nil
+}
+
+func authFromSecret(ctx context.Context, clientset *kubernetes.Clientset, cfg *config.GitConfig, secret *corev1.Secret) (transport.AuthMethod, string, error) {
+	switch cfg.Spec.Protocol {
+	case "http", "https":
+		if token, ok := secret.Data["token"]; ok {
+			return &http.BasicAuth{Username: "token", Password: string(token)}, string(token), nil
+		}
+		username, password := secret.Data["username"], secret.Data["password"]
+		if len(username) == 0 || len(password) == 0 {
+			return nil, "", errors.New("credentials secret must contain either a token key or username/password keys")
+		}
+		return &http.BasicAuth{Username: string(username), Password: string(password)}, string(password), nil
+
+	case "ssh":
+		privateKey, ok := secret.Data["ssh-privatekey"]
+		if !ok {
+			return nil, "", errors.New("credentials secret must contain an ssh-privatekey key for ssh protocol")
+		}
+
+		pKey, err := ssh.NewPublicKeys("git", privateKey, string(secret.Data["ssh-passphrase"]))
+		if err != nil {
+			return nil, "", err
+		}
+
+		if cfg.Spec.KnownHostsConfigMapRef != "" {
+			callback, err := loadHostKeyCallback(ctx, clientset, cfg.Namespace, cfg.Spec.KnownHostsConfigMapRef)
+			if err != nil {
+				return nil, "", err
+			}
+			pKey.HostKeyCallback = callback
+		}
+
+		return pKey, "", nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported protocol: %s", cfg.Spec.Protocol)
+	}
+}
+
+// loadHostKeyCallback builds an ssh.HostKeyCallback that verifies the remote
+// host key against the known_hosts content in the referenced ConfigMap,
+// instead of accepting any host key.
+func loadHostKeyCallback(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (gossh.HostKeyCallback, error) {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts configmap %s/%s: %w", namespace, name, err)
+	}
+	data, ok := cm.Data["known_hosts"]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s has no known_hosts key", namespace, name)
+	}
+
+	// knownhosts.New only reads from a file path, so stage the content in a
+	// temp file that is removed once the callback has been built.
+	tmp, err := os.CreateTemp("", "k8sversioner-known-hosts-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	return knownhosts.New(tmp.Name())
+}
@@ -1,62 +1,65 @@
 package git
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/RafOSS-br/K8sVersioner/config"
+	"github.com/RafOSS-br/K8sVersioner/metrics"
 
 	"github.com/go-git/go-git/v5"
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
-	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"k8s.io/client-go/kubernetes"
 )
 
 type GitClient struct {
-	repo   *git.Repository
-	auth   transport.AuthMethod
-	branch string
-	dir    string
-	push   bool
+	repo        *git.Repository
+	auth        transport.AuthMethod
+	branch      string
+	dir         string
+	push        bool
+	provider    VCSProvider
+	pullRequest config.PullRequestSpec
+	baseBranch  string
+	repoOwner   string
+	repoName    string
+
+	clientset                  *kubernetes.Clientset
+	cfg                        *config.GitConfig
+	credentialsResourceVersion string
+
+	lastPullRequestURL string
+	lastCommitSHA      string
 }
 
-func newHttpAuth(cfg *config.GitConfig) transport.AuthMethod {
-	return &http.BasicAuth{
-		Username: cfg.Spec.Username,
-		Password: cfg.Spec.Password,
+func NewGitClient(ctx context.Context, cfg *config.GitConfig, clientset *kubernetes.Clientset) (*GitClient, error) {
+	creds, err := loadCredentials(ctx, clientset, cfg)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func NewGitClient(ctx context.Context, cfg *config.GitConfig) (*GitClient, error) {
-	var auth transport.AuthMethod
 	var url string
 	switch cfg.Spec.Protocol {
 	case "https":
-		auth = newHttpAuth(cfg)
 		url = "https://" + cfg.Spec.RepositoryURL
 	case "http":
-		auth = newHttpAuth(cfg)
 		url = "http://" + cfg.Spec.RepositoryURL
 	case "ssh":
-		pKey, err := ssh.NewPublicKeysFromFile("git", cfg.Spec.SSHPrivateKeyPath, cfg.Spec.Password)
-		if err != nil {
-			return nil, err
-		}
-		auth = pKey
-		if strings.Contains(cfg.Spec.RepositoryURL, "@") {
-			if user := strings.Split(cfg.Spec.RepositoryURL, "@")[0]; user != "" {
-				cfg.Spec.Username = user
-			}
-		}
 		url = cfg.Spec.RepositoryURL
 	default:
 		return nil, errors.New("unsupported protocol")
 	}
+	auth := creds.auth
+
 	dir := cfg.Spec.RepositoryPath
 	if strings.HasSuffix(dir, "/") {
 		dir = dir + cfg.Spec.RepositoryFolder
@@ -87,16 +90,62 @@ func NewGitClient(ctx context.Context, cfg *config.GitConfig) (*GitClient, error
 		}
 	}
 
+	baseBranch := cfg.Spec.BaseBranch
+	if baseBranch == "" {
+		baseBranch = cfg.Spec.Branch
+	}
+	owner, repoName := ownerRepoFromURL(cfg.Spec.RepositoryURL)
+
+	// Declare this remote as not-yet-reachable until a push actually
+	// succeeds, so /readyz doesn't report ready before it's been verified.
+	metrics.State.RegisterGitRepo(repoName)
+
 	return &GitClient{
-		repo:   repo,
-		auth:   auth,
-		branch: cfg.Spec.Branch,
-		dir:    dir,
-		push:   push,
+		repo:        repo,
+		auth:        auth,
+		branch:      cfg.Spec.Branch,
+		dir:         dir,
+		push:        push,
+		provider:    newProvider(cfg, creds.providerToken),
+		pullRequest: cfg.Spec.PullRequest,
+		baseBranch:  baseBranch,
+		repoOwner:   owner,
+		repoName:    repoName,
+
+		clientset:                  clientset,
+		cfg:                        cfg,
+		credentialsResourceVersion: creds.resourceVersion,
 	}, nil
 }
 
+// refreshCredentialsIfRotated re-reads CredentialsSecretRef and swaps in new
+// auth (and provider token) when its resourceVersion has changed, so rotated
+// tokens are picked up without restarting the process.
+func (g *GitClient) refreshCredentialsIfRotated(ctx context.Context) error {
+	creds, err := loadCredentials(ctx, g.clientset, g.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to refresh credentials: %w", err)
+	}
+	if creds.resourceVersion == g.credentialsResourceVersion {
+		return nil
+	}
+
+	g.auth = creds.auth
+	g.provider = newProvider(g.cfg, creds.providerToken)
+	g.credentialsResourceVersion = creds.resourceVersion
+	return nil
+}
+
+// CommitAndPush commits staged changes and pushes them. When the GitConfig
+// enables PullRequest, it instead pushes to a branch derived from this sync
+// run and opens a pull/merge request against BaseBranch via the configured
+// VCSProvider, so cluster-state changes land as reviews rather than direct
+// pushes.
 func (g *GitClient) CommitAndPush(ctx context.Context, message string) error {
+	if err := g.refreshCredentialsIfRotated(ctx); err != nil {
+		return err
+	}
+
 	w, err := g.repo.Worktree()
 	if err != nil {
 		return err
@@ -108,28 +157,103 @@ func (g *GitClient) CommitAndPush(ctx context.Context, message string) error {
 	}
 
 	// Committing the changes
-	if _, err := w.Commit(message, &git.CommitOptions{}); err != nil {
+	commitHash, err := w.Commit(message, &git.CommitOptions{})
+	if err != nil {
 		return err
 	}
+	g.lastCommitSHA = commitHash.String()
+	metrics.CommitsTotal.WithLabelValues(g.repoName).Inc()
 
 	if !g.push {
 		return nil
 	}
 
+	targetBranch := g.branch
+	if g.pullRequest.Enabled {
+		targetBranch = fmt.Sprintf("k8sversioner/sync-%d", time.Now().Unix())
+	}
+
 	// Pushing the changes
-	if err := g.repo.PushContext(ctx, &git.PushOptions{
+	pushStart := time.Now()
+	pushErr := g.repo.PushContext(ctx, &git.PushOptions{
 		RemoteName: "origin",
 		Auth:       g.auth,
 		RefSpecs: []gitConfig.RefSpec{
-			gitConfig.RefSpec("refs/heads/" + g.branch + ":refs/heads/" + g.branch),
+			gitConfig.RefSpec("refs/heads/" + g.branch + ":refs/heads/" + targetBranch),
 		},
-	}); err != nil && err != git.NoErrAlreadyUpToDate {
-		return err
+	})
+	metrics.GitPushDuration.WithLabelValues(g.repoName).Observe(time.Since(pushStart).Seconds())
+	if pushErr != nil && pushErr != git.NoErrAlreadyUpToDate {
+		metrics.GitPushFailures.WithLabelValues(g.repoName, "push_failed").Inc()
+		metrics.State.SetGitReachable(g.repoName, false)
+		return pushErr
+	}
+	metrics.State.SetGitReachable(g.repoName, true)
+
+	if !g.pullRequest.Enabled {
+		return nil
+	}
+
+	prURL, err := g.openPullRequest(ctx, targetBranch, message)
+	if err != nil {
+		metrics.GitPushFailures.WithLabelValues(g.repoName, "pull_request_failed").Inc()
+		return fmt.Errorf("pushed %s but failed to open pull request: %w", targetBranch, err)
 	}
+	g.lastPullRequestURL = prURL
 
 	return nil
 }
 
+// LastPullRequestURL returns the URL of the pull/merge request opened by the
+// most recent CommitAndPush call, or "" if none was opened.
+func (g *GitClient) LastPullRequestURL() string {
+	return g.lastPullRequestURL
+}
+
+// LastCommitSHA returns the commit hash created by the most recent
+// CommitAndPush call, or "" if none has run yet.
+func (g *GitClient) LastCommitSHA() string {
+	return g.lastCommitSHA
+}
+
+func (g *GitClient) openPullRequest(ctx context.Context, head, commitMessage string) (string, error) {
+	title, err := renderTemplate(g.pullRequest.TitleTemplate, "K8sVersioner sync {{.Branch}}", commitMessage, head)
+	if err != nil {
+		return "", err
+	}
+	body, err := renderTemplate(g.pullRequest.BodyTemplate, "{{.Message}}", commitMessage, head)
+	if err != nil {
+		return "", err
+	}
+
+	return g.provider.OpenPullRequest(ctx, PullRequestOptions{
+		Owner:     g.repoOwner,
+		Repo:      g.repoName,
+		Head:      head,
+		Base:      g.baseBranch,
+		Title:     title,
+		Body:      body,
+		Labels:    g.pullRequest.Labels,
+		Assignees: g.pullRequest.Assignees,
+		AutoMerge: g.pullRequest.AutoMerge,
+	})
+}
+
+func renderTemplate(tmpl, fallback, message, branch string) (string, error) {
+	if tmpl == "" {
+		tmpl = fallback
+	}
+	t, err := template.New("pr").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, struct{ Message, Branch string }{message, branch}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 func (g *GitClient) SaveResource(ctx context.Context, path string, data []byte) error {
 	fullPath := filepath.Join(g.dir, path)
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
@@ -140,3 +264,23 @@ func (g *GitClient) SaveResource(ctx context.Context, path string, data []byte)
 	}
 	return nil
 }
+
+// DeleteResource removes the file backing a deleted Kubernetes resource and
+// stages the removal so the next commit reflects the deletion.
+func (g *GitClient) DeleteResource(ctx context.Context, path string) error {
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(g.dir, path)
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	if _, err := w.Remove(path); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,503 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RafOSS-br/K8sVersioner/config"
+)
+
+// PullRequestOptions describes the pull/merge request to open after pushing
+// a sync branch.
+type PullRequestOptions struct {
+	Owner     string
+	Repo      string
+	Head      string
+	Base      string
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+	AutoMerge bool
+}
+
+// VCSProvider opens a pull/merge request for a pushed branch. Plain git
+// remotes have no concept of PRs, so GoGitProvider reports them unsupported;
+// hosted providers implement this against their REST API.
+type VCSProvider interface {
+	OpenPullRequest(ctx context.Context, opts PullRequestOptions) (string, error)
+}
+
+// newProvider builds the VCSProvider backing PR/MR creation for a GitConfig,
+// defaulting to GoGitProvider when none is configured.
+func newProvider(cfg *config.GitConfig, token string) VCSProvider {
+	switch cfg.Spec.Provider {
+	case "github":
+		return &GitHubProvider{apiURL: defaultAPIURL(cfg.Spec.APIURL, "https://api.github.com"), token: token}
+	case "gitlab":
+		return &GitLabProvider{apiURL: defaultAPIURL(cfg.Spec.APIURL, "https://gitlab.com/api/v4"), token: token}
+	case "gitea":
+		return &GiteaProvider{apiURL: defaultAPIURL(cfg.Spec.APIURL, giteaAPIURL(cfg.Spec.RepositoryURL)), token: token}
+	default:
+		return &GoGitProvider{}
+	}
+}
+
+func defaultAPIURL(configured, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	return fallback
+}
+
+// ownerRepoFromURL extracts "owner/repo" from a repository URL of the form
+// host/owner/repo(.git), which is how RepositoryURL is expressed today.
+func ownerRepoFromURL(repositoryURL string) (owner, repo string) {
+	trimmed := strings.TrimSuffix(repositoryURL, ".git")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+// giteaAPIURL derives a default Gitea API base from RepositoryURL's host
+// (host/owner/repo), since RepositoryURL itself has no scheme and isn't
+// rooted at /api/v1 and so can't be used directly as an API base.
+func giteaAPIURL(repositoryURL string) string {
+	trimmed := strings.TrimSuffix(repositoryURL, ".git")
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	host := strings.Join(parts[:len(parts)-2], "/")
+	return "https://" + host + "/api/v1"
+}
+
+// GoGitProvider backs plain go-git remotes that have no PR/MR API.
+type GoGitProvider struct{}
+
+func (p *GoGitProvider) OpenPullRequest(ctx context.Context, opts PullRequestOptions) (string, error) {
+	return "", fmt.Errorf("provider go-git does not support opening pull requests")
+}
+
+// GitHubProvider opens pull requests via the GitHub REST API.
+type GitHubProvider struct {
+	apiURL string
+	token  string
+}
+
+func (p *GitHubProvider) OpenPullRequest(ctx context.Context, opts PullRequestOptions) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", p.apiURL, opts.Owner, opts.Repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("github: unexpected status creating pull request: %s", resp.Status)
+	}
+
+	var result struct {
+		Number  int    `json:"number"`
+		NodeID  string `json:"node_id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if len(opts.Labels) > 0 || len(opts.Assignees) > 0 {
+		if err := p.applyIssueFields(ctx, opts.Owner, opts.Repo, result.Number, opts.Labels, opts.Assignees); err != nil {
+			return result.HTMLURL, fmt.Errorf("pull request created but failed to apply labels/assignees: %w", err)
+		}
+	}
+	if opts.AutoMerge {
+		if err := p.enableAutoMerge(ctx, result.NodeID); err != nil {
+			return result.HTMLURL, fmt.Errorf("pull request created but failed to enable auto-merge: %w", err)
+		}
+	}
+
+	return result.HTMLURL, nil
+}
+
+// applyIssueFields sets labels and assignees on the pull request via the
+// issues endpoint, which is how GitHub's REST API exposes both for PRs (a
+// pull request is an issue under the hood).
+func (p *GitHubProvider) applyIssueFields(ctx context.Context, owner, repo string, number int, labels, assignees []string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"labels":    labels,
+		"assignees": assignees,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", p.apiURL, owner, repo, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status setting labels/assignees: %s", resp.Status)
+	}
+	return nil
+}
+
+// enableAutoMerge turns on auto-merge for the pull request. GitHub only
+// exposes this via the GraphQL API; there is no REST equivalent.
+func (p *GitHubProvider) enableAutoMerge(ctx context.Context, prNodeID string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     `mutation($id: ID!) { enablePullRequestAutoMerge(input: {pullRequestId: $id}) { clientMutationId } }`,
+		"variables": map[string]string{"id": prNodeID},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.graphQLURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%s", result.Errors[0].Message)
+	}
+	return nil
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST API URL:
+// api.github.com serves GraphQL at api.github.com/graphql, and GitHub
+// Enterprise serves its REST API at <host>/api/v3 with GraphQL alongside it
+// at <host>/api/graphql.
+func (p *GitHubProvider) graphQLURL() string {
+	if strings.HasSuffix(p.apiURL, "/v3") {
+		return strings.TrimSuffix(p.apiURL, "v3") + "graphql"
+	}
+	return strings.TrimSuffix(p.apiURL, "/") + "/graphql"
+}
+
+// GitLabProvider opens merge requests via the GitLab REST API.
+type GitLabProvider struct {
+	apiURL string
+	token  string
+}
+
+func (p *GitLabProvider) OpenPullRequest(ctx context.Context, opts PullRequestOptions) (string, error) {
+	project := opts.Owner + "/" + opts.Repo
+
+	payload := map[string]interface{}{
+		"title":         opts.Title,
+		"description":   opts.Body,
+		"source_branch": opts.Head,
+		"target_branch": opts.Base,
+		"labels":        strings.Join(opts.Labels, ","),
+	}
+	if len(opts.Assignees) > 0 {
+		assigneeIDs, err := p.resolveUserIDs(ctx, opts.Assignees)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve assignees: %w", err)
+		}
+		payload["assignee_ids"] = assigneeIDs
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests", p.apiURL, urlEscape(project))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("gitlab: unexpected status creating merge request: %s", resp.Status)
+	}
+
+	var result struct {
+		IID    int    `json:"iid"`
+		WebURL string `json:"web_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if opts.AutoMerge {
+		if err := p.mergeWhenPipelineSucceeds(ctx, project, result.IID); err != nil {
+			return result.WebURL, fmt.Errorf("merge request created but failed to enable auto-merge: %w", err)
+		}
+	}
+
+	return result.WebURL, nil
+}
+
+// resolveUserIDs looks up GitLab numeric user IDs for the given usernames,
+// since the merge request create API takes assignee_ids rather than names.
+func (p *GitLabProvider) resolveUserIDs(ctx context.Context, usernames []string) ([]int, error) {
+	ids := make([]int, 0, len(usernames))
+	for _, username := range usernames {
+		url := fmt.Sprintf("%s/users?username=%s", p.apiURL, username)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("PRIVATE-TOKEN", p.token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status looking up user %q: %s", username, resp.Status)
+		}
+
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("user %q not found", username)
+		}
+		ids = append(ids, users[0].ID)
+	}
+	return ids, nil
+}
+
+// mergeWhenPipelineSucceeds requests that GitLab merge the MR automatically
+// once its pipeline succeeds, the closest GitLab equivalent of GitHub's
+// auto-merge.
+func (p *GitLabProvider) mergeWhenPipelineSucceeds(ctx context.Context, project string, iid int) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"merge_when_pipeline_succeeds": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%d/merge", p.apiURL, urlEscape(project), iid)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status enabling auto-merge: %s", resp.Status)
+	}
+	return nil
+}
+
+// GiteaProvider opens pull requests via the Gitea REST API, which mirrors
+// GitHub's shape closely enough to share the request/response fields.
+type GiteaProvider struct {
+	apiURL string
+	token  string
+}
+
+func (p *GiteaProvider) OpenPullRequest(ctx context.Context, opts PullRequestOptions) (string, error) {
+	payload := map[string]interface{}{
+		"title": opts.Title,
+		"body":  opts.Body,
+		"head":  opts.Head,
+		"base":  opts.Base,
+	}
+	if len(opts.Assignees) > 0 {
+		payload["assignees"] = opts.Assignees
+	}
+	if len(opts.Labels) > 0 {
+		labelIDs, err := p.resolveLabelIDs(ctx, opts.Owner, opts.Repo, opts.Labels)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve labels: %w", err)
+		}
+		payload["labels"] = labelIDs
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls", p.apiURL, opts.Owner, opts.Repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "token "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("gitea: unexpected status creating pull request: %s", resp.Status)
+	}
+
+	var result struct {
+		Index   int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	if opts.AutoMerge {
+		if err := p.enableAutoMerge(ctx, opts.Owner, opts.Repo, result.Index); err != nil {
+			return result.HTMLURL, fmt.Errorf("pull request created but failed to enable auto-merge: %w", err)
+		}
+	}
+
+	return result.HTMLURL, nil
+}
+
+// resolveLabelIDs looks up the repository's numeric label IDs for the given
+// label names, since Gitea's pull request API takes label IDs rather than
+// names.
+func (p *GiteaProvider) resolveLabelIDs(ctx context.Context, owner, repo string, names []string) ([]int64, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/labels", p.apiURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+p.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status listing labels: %s", resp.Status)
+	}
+
+	var labels []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&labels); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]int64, len(labels))
+	for _, l := range labels {
+		byName[l.Name] = l.ID
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("label %q not found", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// enableAutoMerge schedules the pull request to merge once its required
+// checks succeed, via Gitea's merge endpoint.
+func (p *GiteaProvider) enableAutoMerge(ctx context.Context, owner, repo string, index int) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"Do":                        "merge",
+		"merge_when_checks_succeed": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/merge", p.apiURL, owner, repo, index)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status enabling auto-merge: %s", resp.Status)
+	}
+	return nil
+}
+
+func urlEscape(s string) string {
+	return strings.ReplaceAll(s, "/", "%2F")
+}
@@ -0,0 +1,114 @@
+// Package health implements systemd sd_notify integration: emitting
+// READY=1 once a caller-supplied readiness check passes, and periodic
+// WATCHDOG=1 heartbeats from the controller loop.
+// Every function here is a no-op when NOTIFY_SOCKET isn't set, which is
+// the common case when running in a container without a systemd
+// supervisor.
+package health
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	readyState    = "READY=1"
+	watchdogState = "WATCHDOG=1"
+
+	// notifyPollInterval is how often NotifyWhenReady checks the readiness
+	// callback before emitting READY=1.
+	notifyPollInterval = 500 * time.Millisecond
+)
+
+var (
+	socketOnce sync.Once
+	socketAddr string
+)
+
+// socket resolves NOTIFY_SOCKET on first use and unsets it immediately so
+// subprocesses started later don't also try to notify on our behalf.
+func socket() string {
+	socketOnce.Do(func() {
+		socketAddr = os.Getenv("NOTIFY_SOCKET")
+		os.Unsetenv("NOTIFY_SOCKET")
+	})
+	return socketAddr
+}
+
+// notify sends a single sd_notify datagram, doing nothing if the process
+// wasn't started under systemd (no NOTIFY_SOCKET).
+func notify(state string) {
+	addr := socket()
+	if addr == "" {
+		return
+	}
+
+	conn, err := net.Dial("unixgram", addr)
+	if err != nil {
+		log.Error().Err(err).Str("state", state).Msg("Error dialing systemd notify socket")
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		log.Error().Err(err).Str("state", state).Msg("Error writing to systemd notify socket")
+	}
+}
+
+// NotifyWhenReady polls ready until it reports true (or ctx is canceled)
+// and then sends READY=1, so systemd only considers the unit started once
+// ready does.
+func NotifyWhenReady(ctx context.Context, ready func() bool) {
+	if socket() == "" {
+		return
+	}
+
+	ticker := time.NewTicker(notifyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if ready() {
+			notify(readyState)
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// StartWatchdog sends WATCHDOG=1 on the interval systemd advertises via
+// WATCHDOG_USEC (halved, per the sd_watchdog_enabled(3) convention) until
+// ctx is canceled. It is a no-op when NOTIFY_SOCKET or WATCHDOG_USEC isn't
+// set, i.e. the unit's systemd service file has no WatchdogSec=.
+func StartWatchdog(ctx context.Context) {
+	if socket() == "" {
+		return
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return
+	}
+
+	interval := time.Duration(usec) * time.Microsecond / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			notify(watchdogState)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
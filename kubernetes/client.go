@@ -1,6 +1,10 @@
 package kubernetes
 
 import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -24,6 +28,49 @@ func GetKubernetesConfig() (*K8sClient, error) {
 		}
 	}
 
+	return newK8sClient(config)
+}
+
+// GetKubernetesConfigFromPath builds a K8sClient from an explicit kubeconfig
+// path, falling back to GetKubernetesConfig's in-cluster/default-kubeconfig
+// resolution when path is empty. It exists for commands like `init
+// --kubeconfig` that need to target a cluster other than the ambient one.
+func GetKubernetesConfigFromPath(path string) (*K8sClient, error) {
+	if path == "" {
+		return GetKubernetesConfig()
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newK8sClient(config)
+}
+
+// GetKubernetesConfigForSecret builds a K8sClient for a remote cluster from a
+// kubeconfig blob stored under the "kubeconfig" key of a Secret, so a single
+// K8sVersioner instance can mirror multiple clusters into one repo.
+func GetKubernetesConfigForSecret(ctx context.Context, mgmtClientset *kubernetes.Clientset, namespace, secretName string) (*K8sClient, error) {
+	secret, err := mgmtClientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	kubeconfig, ok := secret.Data["kubeconfig"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no kubeconfig key", namespace, secretName)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig from secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	return newK8sClient(restConfig)
+}
+
+func newK8sClient(config *rest.Config) (*K8sClient, error) {
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/rs/zerolog"
+	"k8s.io/klog/v2"
+)
+
+// installKlogBridge redirects klog's output onto logger instead of klog's
+// own writer, so the chatter client-go and controller-runtime emit through
+// klog ends up in the same console/JSON sink as the rest of the app.
+func installKlogBridge(logger zerolog.Logger) {
+	klog.SetLogger(logr.New(&klogSink{logger: logger.With().Str("component", "klog").Logger()}))
+}
+
+// klogSink adapts a zerolog.Logger to logr.LogSink, the interface
+// klog.SetLogger expects.
+type klogSink struct {
+	logger zerolog.Logger
+	name   string
+}
+
+func (s *klogSink) Init(logr.RuntimeInfo) {}
+
+// Enabled defers to the global zerolog level rather than tracking klog's
+// per-call verbosity, so -v flags passed to klog don't need their own
+// plumbing; callers control verbosity via Options.Level.
+func (s *klogSink) Enabled(int) bool {
+	return true
+}
+
+func (s *klogSink) Info(_ int, msg string, kv ...interface{}) {
+	logger := s.logger
+	if len(kv) > 0 {
+		logger = withKV(logger.With(), kv).Logger()
+	}
+	s.annotate(logger.Info()).Msg(msg)
+}
+
+func (s *klogSink) Error(err error, msg string, kv ...interface{}) {
+	logger := s.logger
+	if len(kv) > 0 {
+		logger = withKV(logger.With(), kv).Logger()
+	}
+	s.annotate(logger.Error().Err(err)).Msg(msg)
+}
+
+func (s *klogSink) WithValues(kv ...interface{}) logr.LogSink {
+	return &klogSink{logger: withKV(s.logger.With(), kv).Logger(), name: s.name}
+}
+
+func (s *klogSink) WithName(name string) logr.LogSink {
+	full := name
+	if s.name != "" {
+		full = s.name + "." + name
+	}
+	return &klogSink{logger: s.logger, name: full}
+}
+
+func (s *klogSink) annotate(event *zerolog.Event) *zerolog.Event {
+	if s.name != "" {
+		event = event.Str("logger", s.name)
+	}
+	return event
+}
@@ -1,19 +1,14 @@
 package main
 
 import (
-	"os"
-	"time"
-
-	"github.com/rs/zerolog/log"
-
 	"github.com/RafOSS-br/K8sVersioner/cmd"
-	"github.com/rs/zerolog"
+	applog "github.com/RafOSS-br/K8sVersioner/log"
 )
 
 func main() {
-	zerolog.TimeFieldFormat = time.RFC3339
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	// A sane default logger in case anything logs before cmd.Execute()
+	// parses --log-level/--log-format and re-runs applog.Init.
+	applog.Init(applog.Options{})
 
 	cmd.Execute()
 }
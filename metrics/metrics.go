@@ -0,0 +1,142 @@
+// Package metrics exposes the Prometheus counters/histograms/gauges tracked
+// across a sync run, plus the HTTP handlers backing the /metrics, /healthz,
+// and /readyz endpoints used by Kubernetes probes and a Prometheus Operator
+// ServiceMonitor.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	SyncTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8sversioner_sync_total",
+		Help: "Total number of Config sync attempts, labeled by Config name and outcome.",
+	}, []string{"config", "result"})
+
+	SyncDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "k8sversioner_sync_duration_seconds",
+		Help: "Duration of a Config sync, from listing resources to the commit being pushed.",
+	}, []string{"config"})
+
+	CommitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8sversioner_commits_total",
+		Help: "Total number of commits created in the target repository, labeled by repository.",
+	}, []string{"repo"})
+
+	ResourcesWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8sversioner_resources_written_total",
+		Help: "Total number of resources written to Git, labeled by GroupVersionKind and namespace.",
+	}, []string{"gvk", "namespace"})
+
+	GitPushDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "k8sversioner_git_push_duration_seconds",
+		Help: "Duration of the push performed by CommitAndPush, labeled by repository.",
+	}, []string{"repo"})
+
+	GitPushFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8sversioner_git_push_failures_total",
+		Help: "Total number of failed Git pushes and pull request creations, labeled by repository and failure reason (push_failed, pull_request_failed).",
+	}, []string{"repo", "reason"})
+
+	LastSuccessfulSync = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "k8sversioner_last_successful_sync_timestamp_seconds",
+		Help: "Unix timestamp of the last successful sync, labeled by Config name.",
+	}, []string{"config"})
+)
+
+// State is the process-wide readiness tracker queried by the /readyz
+// handler. It mirrors the package-level promauto vars above: a single
+// shared instance rather than threading a reference through every caller.
+var State = NewReadiness()
+
+// Readiness tracks the preconditions a /readyz probe cares about: that the
+// initial ConfigManager load succeeded, and that every Git remote touched so
+// far is reachable.
+type Readiness struct {
+	mu           sync.RWMutex
+	configLoaded bool
+	gitReachable map[string]bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{gitReachable: make(map[string]bool)}
+}
+
+// SetConfigLoaded records whether the initial ConfigManager load succeeded.
+func (r *Readiness) SetConfigLoaded(ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configLoaded = ok
+}
+
+// SetGitReachable records whether the given Git remote was reachable the
+// last time it was used.
+func (r *Readiness) SetGitReachable(repo string, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gitReachable[repo] = ok
+}
+
+// RegisterGitRepo pre-declares a configured Git remote as not yet verified,
+// so Ready() reports false for it until a push has actually succeeded
+// instead of treating a remote SetGitReachable hasn't been called for yet as
+// reachable by default.
+func (r *Readiness) RegisterGitRepo(repo string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.gitReachable[repo]; !ok {
+		r.gitReachable[repo] = false
+	}
+}
+
+// Ready reports whether the config has loaded and every known Git remote is
+// currently reachable.
+func (r *Readiness) Ready() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.configLoaded {
+		return false
+	}
+	for _, ok := range r.gitReachable {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// StartServer runs the /metrics, /healthz, and /readyz HTTP endpoints until
+// ctx is canceled.
+func StartServer(ctx context.Context, addr string, readiness *Readiness) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if readiness.Ready() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}